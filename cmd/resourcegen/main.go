@@ -0,0 +1,74 @@
+// resourcegen generates Go structs for CloudFormation resource and property
+// types, one package per service.
+//
+// Usage:
+//
+//	go run ./cmd/resourcegen -output resources
+//	go run ./cmd/resourcegen -region eu-west-1 -version 150.0.0
+//	go run ./cmd/resourcegen -input CloudFormationResourceSpecification.json
+//
+// This will write resources/<service>/<service>.go for every service.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/lex00/cloudformation-schema-go/codegen"
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+var (
+	outputDir = flag.String("output", "resources", "output directory for the generated packages")
+	region    = flag.String("region", spec.DefaultRegion, "region of the spec")
+	version   = flag.String("version", spec.LatestVersion, "spec version")
+	input     = flag.String("input", "", "read the spec from a local JSON file instead of downloading")
+)
+
+func main() {
+	flag.Parse()
+
+	var cfSpec *spec.Spec
+	var err error
+	if *input != "" {
+		cfSpec, err = spec.LoadSpec(*input)
+	} else {
+		cfSpec, err = spec.FetchSpec(&spec.FetchOptions{
+			Region:  *region,
+			Version: *version,
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load spec: %v\n", err)
+		os.Exit(1)
+	}
+
+	files, err := codegen.GenerateResources(cfSpec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate resources: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Write in sorted order for deterministic progress output.
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		target := filepath.Join(*outputDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create %s: %v\n", filepath.Dir(target), err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(target, files[path], 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", target, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Generated %s\n", target)
+	}
+}
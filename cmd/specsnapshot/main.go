@@ -0,0 +1,124 @@
+// specsnapshot writes a compressed snapshot of the CloudFormation spec for
+// the specembed package.
+//
+// Usage:
+//
+//	go run ./cmd/specsnapshot
+//	go run ./cmd/specsnapshot -region eu-west-1 -version 150.0.0
+//	go run ./cmd/specsnapshot -input CloudFormationResourceSpecification.json
+//
+// This will write specembed/spec.json.gz and specembed/version.go.
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+var (
+	outputDir = flag.String("output", "specembed", "output directory for the snapshot")
+	region    = flag.String("region", spec.DefaultRegion, "region to snapshot")
+	version   = flag.String("version", spec.LatestVersion, "spec version to snapshot")
+	input     = flag.String("input", "", "read the spec from a local JSON file instead of downloading")
+)
+
+// VersionData is the data for generating version.go.
+type VersionData struct {
+	Version string
+	Region  string
+}
+
+func main() {
+	flag.Parse()
+
+	var cfSpec *spec.Spec
+	var err error
+	if *input != "" {
+		cfSpec, err = spec.LoadSpec(*input)
+	} else {
+		cfSpec, err = spec.FetchSpec(&spec.FetchOptions{
+			Region:  *region,
+			Version: *version,
+			Force:   true,
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load spec: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeSnapshot(cfSpec, *outputDir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write snapshot: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Generated %s/spec.json.gz\n", *outputDir)
+
+	data := VersionData{Version: cfSpec.ResourceSpecificationVersion, Region: *region}
+	if err := generateVersionFile(data, *outputDir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate version.go: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Generated %s/version.go\n", *outputDir)
+}
+
+// writeSnapshot re-encodes the spec so the output is deterministic:
+// encoding/json sorts map keys and the gzip header carries no timestamp.
+func writeSnapshot(cfSpec *spec.Spec, outputDir string) error {
+	data, err := json.Marshal(cfSpec)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := gz.Write(data); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(outputDir, "spec.json.gz"), buf.Bytes(), 0644)
+}
+
+const versionTemplate = `// Code generated by specsnapshot. DO NOT EDIT.
+
+package specembed
+
+// Version is the ResourceSpecificationVersion of the embedded snapshot.
+const Version = "{{.Version}}"
+
+// Region is the region the embedded snapshot was taken from.
+const Region = "{{.Region}}"
+`
+
+func generateVersionFile(data VersionData, outputDir string) error {
+	tmpl, err := template.New("version").Parse(versionTemplate)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		formatted = buf.Bytes()
+	}
+
+	return os.WriteFile(filepath.Join(outputDir, "version.go"), formatted, 0644)
+}
@@ -0,0 +1,61 @@
+// templateschema writes a JSON Schema for CloudFormation templates,
+// generated from the resource specification.
+//
+// Usage:
+//
+//	go run ./cmd/templateschema
+//	go run ./cmd/templateschema -region eu-west-1 -version 150.0.0
+//	go run ./cmd/templateschema -input CloudFormationResourceSpecification.json -output cfn.schema.json
+//
+// Point the YAML language server at the output, e.g. in VS Code:
+//
+//	"yaml.schemas": {"./cloudformation.schema.json": "templates/*.yaml"}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lex00/cloudformation-schema-go/jsonschema"
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+var (
+	output      = flag.String("output", "cloudformation.schema.json", "output file for the schema")
+	region      = flag.String("region", spec.DefaultRegion, "region of the spec")
+	version     = flag.String("version", spec.LatestVersion, "spec version")
+	input       = flag.String("input", "", "read the spec from a local JSON file instead of downloading")
+	id          = flag.String("id", "", "$id of the generated schema")
+	strictEnums = flag.Bool("strict-enums", false, "restrict enum-mapped properties to their allowed values")
+)
+
+func main() {
+	flag.Parse()
+
+	var cfSpec *spec.Spec
+	var err error
+	if *input != "" {
+		cfSpec, err = spec.LoadSpec(*input)
+	} else {
+		cfSpec, err = spec.FetchSpec(&spec.FetchOptions{
+			Region:  *region,
+			Version: *version,
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load spec: %v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := jsonschema.Generate(cfSpec, &jsonschema.Options{ID: *id, StrictEnums: *strictEnums})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate schema: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, append(data, '\n'), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Generated %s (spec %s)\n", *output, cfSpec.ResourceSpecificationVersion)
+}
@@ -28,4 +28,11 @@
 // Sort nodes by dependencies using Kahn's algorithm:
 //
 //	sorted := TopologicalSort(nodes, getDeps)
+//
+// # Resource Structs
+//
+// Generate Go structs for resource and property types, one package per
+// service (see cmd/resourcegen):
+//
+//	files, err := GenerateResources(cfSpec) // "s3/s3.go" -> source
 package codegen
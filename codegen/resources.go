@@ -0,0 +1,437 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+// GenerateResources generates Go structs for every resource and property
+// type of the provider, one package per service. The result maps file paths
+// such as "s3/s3.go" to formatted source; output is deterministic.
+//
+// Resource structs have one field per property and a ResourceOptions field
+// for DependsOn, Condition and the other resource attributes. Their
+// MarshalJSON produces {"Type": ..., "Properties": ...}. Fields have the
+// typed values of the intrinsics package, so they accept literals and
+// intrinsics of the right type alike: intrinsics.StringValue for a String,
+// intrinsics.IntValue for an Integer, intrinsics.DoubleValue for a Double,
+// intrinsics.ListValue[intrinsics.StringValue] for a List of String, and
+// intrinsics.ObjectValue[T] for a property type T, which a *T or an
+// intrinsic wrapped in intrinsics.ObjectOf satisfies. Json fields are any.
+// Required fields have no omitempty. Property types referenced from a
+// service by their bare name (such as "Tag") are generated into each
+// package that uses them, and property type names repeating the resource
+// name, such as AWS::S3::Bucket.BucketEncryption, are not prefixed with
+// it again.
+//
+// A spec with structural errors is rejected with a *spec.CheckError.
+func GenerateResources(p spec.SchemaProvider) (map[string][]byte, error) {
+	s, err := spec.Materialize(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := spec.Verify(s); err != nil {
+		return nil, err
+	}
+	packages := make(map[string]*resourcePackage)
+
+	resourceTypes := s.ResourceTypeNames()
+	sort.Strings(resourceTypes)
+	for _, typeName := range resourceTypes {
+		pkgName, structName := resourcePackageName(typeName)
+		pkg, ok := packages[pkgName]
+		if !ok {
+			pkg = newResourcePackage(s, pkgName, typeName)
+			packages[pkgName] = pkg
+		}
+		pkg.resourceTypes = append(pkg.resourceTypes, resourceEntry{typeName: typeName, name: structName})
+	}
+
+	files := make(map[string][]byte, len(packages))
+	for name, pkg := range packages {
+		src, err := pkg.generate()
+		if err != nil {
+			return nil, fmt.Errorf("generating package %s: %w", name, err)
+		}
+		files[path.Join(name, name+".go")] = src
+	}
+	return files, nil
+}
+
+// resourcePackageName returns the package and struct name for a resource type:
+// "s3" and "Bucket" for "AWS::S3::Bucket", "alexaask" and "Skill" for
+// "Alexa::ASK::Skill".
+func resourcePackageName(typeName string) (pkg, name string) {
+	parts := strings.Split(typeName, "::")
+	if len(parts) < 3 {
+		return "resources", SanitizeGoIdentifier(strings.Join(parts, ""))
+	}
+	pkg = parts[1]
+	if parts[0] != "AWS" {
+		pkg = parts[0] + parts[1]
+	}
+	pkg = SanitizeGoIdentifier(strings.ToLower(pkg))
+	return pkg, SanitizeGoIdentifier(strings.Join(parts[2:], ""))
+}
+
+// resourcePackage collects the types of one generated package.
+type resourcePackage struct {
+	spec          *spec.Spec
+	name          string
+	service       string
+	resourceTypes []resourceEntry
+}
+
+type resourceEntry struct {
+	typeName string
+	name     string
+}
+
+func newResourcePackage(s *spec.Spec, name, typeName string) *resourcePackage {
+	parts := strings.Split(typeName, "::")
+	return &resourcePackage{spec: s, name: name, service: strings.Join(parts[:len(parts)-1], "::")}
+}
+
+// resourceData is the template data for a generated package.
+type resourceData struct {
+	Package        string
+	Service        string
+	Version        string
+	Resources      []structData
+	PropertyTypes  []structData
+	UsesIntrinsics bool // whether a field has an intrinsics type
+}
+
+// structData is a generated struct.
+type structData struct {
+	Name          string
+	TypeName      string // CloudFormation type name
+	Documentation string
+	Deprecated    string
+	Fields        []fieldData
+	OptionsField  string // name of the ResourceOptions field (resources only)
+}
+
+// fieldData is a field of a generated struct.
+type fieldData struct {
+	Name     string
+	JSONName string
+	GoType   string
+	Comment  []string
+	Required bool
+}
+
+func (pkg *resourcePackage) generate() ([]byte, error) {
+	data := resourceData{
+		Package: pkg.name,
+		Service: pkg.service,
+		Version: pkg.spec.ResourceSpecificationVersion,
+	}
+
+	// Struct names of property types, assigned as they are referenced.
+	propertyTypeNames := make(map[string]string)
+	taken := make(map[string]bool)
+	for _, r := range pkg.resourceTypes {
+		taken[r.name] = true
+	}
+	taken["ResourceOptions"] = true
+
+	var pending []string
+	structName := func(resourceType, typeName string) string {
+		fullName, pt := pkg.spec.ResolvePropertyType(resourceType, typeName)
+		if pt == nil {
+			return ""
+		}
+		if name, ok := propertyTypeNames[fullName]; ok {
+			return name
+		}
+		resourceName, propertyName := spec.ParsePropertyTypeName(fullName)
+		name := SanitizeGoIdentifier(propertyName)
+		if propertyName == "" {
+			// Shared property type such as "Tag".
+			name = SanitizeGoIdentifier(resourceName)
+		} else if _, resource := resourcePackageName(resourceName); !stutters(name, resource) {
+			name = resource + name
+		}
+		for taken[name] {
+			name += "Property"
+		}
+		taken[name] = true
+		propertyTypeNames[fullName] = name
+		pending = append(pending, fullName)
+		return name
+	}
+
+	for _, r := range pkg.resourceTypes {
+		rt := pkg.spec.ResourceTypes[r.typeName]
+		sd := structData{
+			Name:          r.name,
+			TypeName:      r.typeName,
+			Documentation: rt.Documentation,
+			Fields:        fields(r.typeName, rt.Properties, structName),
+			OptionsField:  "ResourceOptions",
+		}
+		if d := spec.ResourceTypeDeprecation(r.typeName); d != nil {
+			sd.Deprecated = d.String()
+		}
+		for hasField(sd.Fields, sd.OptionsField) {
+			sd.OptionsField += "_"
+		}
+		data.Resources = append(data.Resources, sd)
+	}
+
+	// Property types may reference further property types.
+	for i := 0; i < len(pending); i++ {
+		fullName := pending[i]
+		pt := pkg.spec.PropertyTypes[fullName]
+		data.PropertyTypes = append(data.PropertyTypes, structData{
+			Name:          propertyTypeNames[fullName],
+			TypeName:      fullName,
+			Documentation: pt.Documentation,
+			Fields:        fields(fullName, pt.Properties, structName),
+		})
+	}
+	for _, structs := range [][]structData{data.Resources, data.PropertyTypes} {
+		for _, sd := range structs {
+			for _, f := range sd.Fields {
+				if strings.Contains(f.GoType, "intrinsics.") {
+					data.UsesIntrinsics = true
+				}
+			}
+		}
+	}
+	sort.Slice(data.PropertyTypes, func(i, j int) bool {
+		return data.PropertyTypes[i].Name < data.PropertyTypes[j].Name
+	})
+
+	tmpl, err := template.New("resources").Parse(resourcesTemplate)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// stutters reports whether a property type name such as "BucketEncryption"
+// already starts with the name of its resource, "Bucket", as a whole word.
+func stutters(name, resource string) bool {
+	rest, ok := strings.CutPrefix(name, resource)
+	return ok && rest != "" && unicode.IsUpper(rune(rest[0]))
+}
+
+// fields returns the struct fields for a set of properties of the owner
+// resource or property type, sorted by name. structName returns the struct
+// for a property type, or "" if it is undefined.
+func fields(owner string, props map[string]spec.Property, structName func(resourceType, typeName string) string) []fieldData {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	resourceType, _ := spec.ParsePropertyTypeName(owner)
+
+	var result []fieldData
+	for _, name := range names {
+		prop := props[name]
+		f := fieldData{
+			Name:     SanitizeGoIdentifier(name),
+			JSONName: name,
+			GoType:   goType(resourceType, prop, structName),
+			Required: prop.Required,
+		}
+
+		summary := "is optional."
+		if prop.Required {
+			summary = "is required."
+		}
+		if prop.UpdateType != "" {
+			summary += " Update type: " + prop.UpdateType + "."
+		}
+		f.Comment = append(f.Comment, f.Name+" "+summary)
+		if kind := typeDescription(prop); kind != "" {
+			f.Comment = append(f.Comment, "Type: "+kind+".")
+		}
+		if prop.Documentation != "" {
+			f.Comment = append(f.Comment, "See "+prop.Documentation)
+		}
+		if d := spec.PropertyDeprecation(owner, name); d != nil {
+			f.Comment = append(f.Comment, "", "Deprecated: "+d.String())
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+// goType returns the Go type of a property field.
+func goType(resourceType string, prop spec.Property, structName func(resourceType, typeName string) string) string {
+	item := primitiveGoType(prop.PrimitiveItemType)
+	if prop.ItemType != "" {
+		item = objectGoType(structName(resourceType, prop.ItemType))
+	}
+	switch {
+	case prop.IsList():
+		return "intrinsics.ListValue[" + item + "]"
+	case prop.IsMap():
+		return "map[string]" + item
+	case prop.IsComplex():
+		return objectGoType(structName(resourceType, prop.Type))
+	}
+	return primitiveGoType(prop.PrimitiveType)
+}
+
+// primitiveGoType returns the typed value for a primitive type, or any for
+// Json and unknown types.
+func primitiveGoType(primitive string) string {
+	switch primitive {
+	case "String", "Timestamp":
+		return "intrinsics.StringValue"
+	case "Integer", "Long":
+		return "intrinsics.IntValue"
+	case "Double":
+		return "intrinsics.DoubleValue"
+	case "Boolean":
+		return "intrinsics.BoolValue"
+	}
+	return "any"
+}
+
+// objectGoType returns the typed value for a property type struct, or any
+// if the property type is undefined.
+func objectGoType(name string) string {
+	if name == "" {
+		return "any"
+	}
+	return "intrinsics.ObjectValue[" + name + "]"
+}
+
+// typeDescription describes the CloudFormation type of a property, e.g.
+// "String" or "List of String".
+func typeDescription(prop spec.Property) string {
+	item := prop.PrimitiveItemType
+	if item == "" {
+		item = prop.ItemType
+	}
+	switch {
+	case prop.IsList() || prop.IsMap():
+		return prop.Type + " of " + item
+	case prop.PrimitiveType != "":
+		return prop.PrimitiveType
+	}
+	return ""
+}
+
+func hasField(fields []fieldData, name string) bool {
+	for _, f := range fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+const resourcesTemplate = `// Code generated by resourcegen from CloudFormation resource specification {{.Version}}. DO NOT EDIT.
+
+// Package {{.Package}} contains structs for the {{.Service}} resource types.
+package {{.Package}}
+
+import (
+	"encoding/json"
+{{- if .UsesIntrinsics}}
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+{{- end}}
+)
+
+// ResourceOptions are the resource attributes that apply to every resource.
+type ResourceOptions struct {
+	DependsOn           []string
+	Condition           string
+	DeletionPolicy      string
+	UpdateReplacePolicy string
+	Metadata            map[string]any
+}
+
+// marshalResource encodes a resource as {"Type": ..., "Properties": ...}
+// followed by its resource attributes.
+func marshalResource(typeName string, properties any, opts ResourceOptions) ([]byte, error) {
+	props, err := json.Marshal(properties)
+	if err != nil {
+		return nil, err
+	}
+	if string(props) == "{}" {
+		props = nil
+	}
+	return json.Marshal(struct {
+		Type                string
+		Properties          json.RawMessage ` + "`json:\",omitempty\"`" + `
+		DependsOn           []string        ` + "`json:\",omitempty\"`" + `
+		Condition           string          ` + "`json:\",omitempty\"`" + `
+		DeletionPolicy      string          ` + "`json:\",omitempty\"`" + `
+		UpdateReplacePolicy string          ` + "`json:\",omitempty\"`" + `
+		Metadata            map[string]any  ` + "`json:\",omitempty\"`" + `
+	}{typeName, props, opts.DependsOn, opts.Condition, opts.DeletionPolicy, opts.UpdateReplacePolicy, opts.Metadata})
+}
+{{range .Resources}}
+// {{.Name}} is the {{.TypeName}} resource type.
+{{- if .Documentation}}
+//
+// See {{.Documentation}}
+{{- end}}
+{{- if .Deprecated}}
+//
+// Deprecated: {{.Deprecated}}
+{{- end}}
+type {{.Name}} struct {
+{{- range .Fields}}
+{{- range .Comment}}
+	//{{if .}} {{.}}{{end}}
+{{- end}}
+	{{.Name}} {{.GoType}} ` + "`" + `json:"{{.JSONName}}{{if not .Required}},omitempty{{end}}"` + "`" + `
+{{end}}
+	// {{.OptionsField}} holds DependsOn, Condition and the other resource attributes.
+	{{.OptionsField}} ResourceOptions ` + "`" + `json:"-"` + "`" + `
+}
+
+// AWSCloudFormationType returns "{{.TypeName}}".
+func ({{.Name}}) AWSCloudFormationType() string {
+	return "{{.TypeName}}"
+}
+
+// MarshalJSON encodes the resource as {"Type": "{{.TypeName}}", "Properties": ...}.
+func (r {{.Name}}) MarshalJSON() ([]byte, error) {
+	type properties {{.Name}}
+	return marshalResource("{{.TypeName}}", properties(r), r.{{.OptionsField}})
+}
+{{end}}
+{{- range .PropertyTypes}}
+// {{.Name}} is the {{.TypeName}} property type.
+{{- if .Documentation}}
+//
+// See {{.Documentation}}
+{{- end}}
+type {{.Name}} struct {
+{{- range .Fields}}
+{{- range .Comment}}
+	//{{if .}} {{.}}{{end}}
+{{- end}}
+	{{.Name}} {{.GoType}} ` + "`" + `json:"{{.JSONName}}{{if not .Required}},omitempty{{end}}"` + "`" + `
+{{end -}}
+}
+
+// CloudFormationObject returns p, making a *{{.Name}} an
+// intrinsics.ObjectValue[{{.Name}}].
+func (p *{{.Name}}) CloudFormationObject() *{{.Name}} {
+	return p
+}
+{{end}}`
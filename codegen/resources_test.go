@@ -0,0 +1,291 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+	"github.com/lex00/cloudformation-schema-go/specembed"
+)
+
+func TestResourcePackageName(t *testing.T) {
+	tests := []struct {
+		typeName string
+		pkg      string
+		name     string
+	}{
+		{"AWS::S3::Bucket", "s3", "Bucket"},
+		{"AWS::EC2::VPC", "ec2", "VPC"},
+		{"Alexa::ASK::Skill", "alexaask", "Skill"},
+		{"AWS::Serverless::Function", "serverless", "Function"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			pkg, name := resourcePackageName(tt.typeName)
+			if pkg != tt.pkg || name != tt.name {
+				t.Errorf("resourcePackageName(%q) = %q, %q, want %q, %q", tt.typeName, pkg, name, tt.pkg, tt.name)
+			}
+		})
+	}
+}
+
+func TestGenerateResources(t *testing.T) {
+	s, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("specembed.Load() error: %v", err)
+	}
+
+	files, err := GenerateResources(s)
+	if err != nil {
+		t.Fatalf("GenerateResources() error: %v", err)
+	}
+
+	src, ok := files["s3/s3.go"]
+	if !ok {
+		t.Fatal("missing s3/s3.go")
+	}
+	code := string(src)
+	for _, want := range []string{
+		"// Code generated by resourcegen",
+		"package s3",
+		"type Bucket struct {",
+		"BucketName intrinsics.StringValue `json:\"BucketName,omitempty\"`",
+		"BucketEncryption intrinsics.ObjectValue[BucketEncryption] `json:\"BucketEncryption,omitempty\"`",
+		"ServerSideEncryptionConfiguration intrinsics.ListValue[intrinsics.ObjectValue[BucketServerSideEncryptionRule]] `json:\"ServerSideEncryptionConfiguration\"`",
+		"Tags intrinsics.ListValue[intrinsics.ObjectValue[Tag]] `json:\"Tags,omitempty\"`",
+		"type BucketEncryption struct {",
+		"func (p *BucketEncryption) CloudFormationObject() *BucketEncryption {",
+		"ResourceOptions ResourceOptions `json:\"-\"`",
+		"func (Bucket) AWSCloudFormationType() string",
+		"return marshalResource(\"AWS::S3::Bucket\", properties(r), r.ResourceOptions)",
+		"type Tag struct {",
+		"// Deprecated: AWS::S3::Bucket.AccessControl is legacy",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("s3/s3.go missing %q", want)
+		}
+	}
+
+	if !strings.Contains(string(files["sdb/sdb.go"]), "// Deprecated: AWS::SDB::Domain is legacy") {
+		t.Error("expected AWS::SDB::Domain to be marked deprecated")
+	}
+
+	// Every file must type-check.
+	for path, src := range files {
+		if err := typeCheck(path, src); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
+
+// The source importer caches intrinsics once type-checked.
+var (
+	checkFset     = token.NewFileSet()
+	checkImporter = importer.ForCompiler(checkFset, "source", nil)
+)
+
+// typeCheck parses and type-checks a generated package, with extra source
+// files of the same package.
+func typeCheck(path string, src []byte, extra ...string) error {
+	fset := checkFset
+	var parsed []*ast.File
+	for i, file := range append([]string{string(src)}, extra...) {
+		name := path
+		if i > 0 {
+			name = strings.TrimSuffix(path, ".go") + "_extra.go"
+		}
+		f, err := parser.ParseFile(fset, name, file, parser.AllErrors)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, f)
+	}
+	conf := types.Config{Importer: checkImporter}
+	_, err := conf.Check(path, fset, parsed, nil)
+	return err
+}
+
+func TestGenerateResources_TypedFields(t *testing.T) {
+	s, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("specembed.Load() error: %v", err)
+	}
+	files, err := GenerateResources(s)
+	if err != nil {
+		t.Fatalf("GenerateResources() error: %v", err)
+	}
+
+	const usage = `package s3
+
+import "github.com/lex00/cloudformation-schema-go/intrinsics"
+
+var _ = Bucket{
+	BucketName: intrinsics.Sub{String: "${AWS::StackName}-logs"},
+	BucketEncryption: intrinsics.ObjectOf[BucketEncryption]{Value: intrinsics.If{
+		Condition:    "Encrypt",
+		ValueIfTrue:  &BucketEncryption{},
+		ValueIfFalse: intrinsics.AWS_NO_VALUE,
+	}},
+	Tags: intrinsics.List[intrinsics.ObjectValue[Tag]]{
+		&Tag{Key: intrinsics.String("Env"), Value: intrinsics.Ref{LogicalName: "Env"}},
+	},
+}
+`
+	if err := typeCheck("s3/s3.go", files["s3/s3.go"], usage); err != nil {
+		t.Errorf("typed usage does not compile: %v", err)
+	}
+
+	tests := map[string]string{
+		"list_as_string": `BucketName: intrinsics.Strings("a")`,
+		"int_as_string":  `BucketName: intrinsics.Int(1)`,
+		"wrong_object":   `BucketEncryption: &Tag{}`,
+		"string_as_list": `Tags: intrinsics.String("a")`,
+	}
+	for name, field := range tests {
+		t.Run(name, func(t *testing.T) {
+			src := "package s3\n\nimport \"github.com/lex00/cloudformation-schema-go/intrinsics\"\n\nvar _ = intrinsics.String(\"\")\n\nvar _ = Bucket{" + field + "}\n"
+			if err := typeCheck("s3/s3.go", files["s3/s3.go"], src); err == nil {
+				t.Errorf("expected %s not to compile", field)
+			}
+		})
+	}
+}
+
+func TestGenerateResources_Deterministic(t *testing.T) {
+	s, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("specembed.Load() error: %v", err)
+	}
+
+	first, err := GenerateResources(s)
+	if err != nil {
+		t.Fatalf("GenerateResources() error: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		again, err := GenerateResources(s)
+		if err != nil {
+			t.Fatalf("GenerateResources() error: %v", err)
+		}
+		if len(again) != len(first) {
+			t.Fatalf("got %d files, want %d", len(again), len(first))
+		}
+		for path, src := range first {
+			if !bytes.Equal(again[path], src) {
+				t.Errorf("%s differs between runs", path)
+			}
+		}
+	}
+}
+
+func TestGenerateResources_Collisions(t *testing.T) {
+	s := &spec.Spec{
+		ResourceSpecificationVersion: "1.0.0",
+		ResourceTypes: map[string]spec.ResourceType{
+			"AWS::Test::Thing": {
+				Properties: map[string]spec.Property{
+					"ResourceOptions": {PrimitiveType: "String"},
+					"Child":           {Type: "Child", Required: true},
+					"Self":            {Type: "Node", Required: true},
+					"Settings":        {Type: "ThingSettings"},
+					"Gadget":          {Type: "Thingamajig"},
+					"Weight":          {PrimitiveType: "Double"},
+				},
+			},
+			"AWS::Test::ThingChild": {},
+		},
+		PropertyTypes: map[string]spec.PropertyType{
+			"AWS::Test::Thing.Child": {
+				Properties: map[string]spec.Property{
+					"Name": {PrimitiveType: "String", Required: true},
+				},
+			},
+			"AWS::Test::Thing.ThingSettings": {
+				Properties: map[string]spec.Property{"Name": {PrimitiveType: "String"}},
+			},
+			"AWS::Test::Thing.Thingamajig": {
+				Properties: map[string]spec.Property{"Name": {PrimitiveType: "String"}},
+			},
+			"AWS::Test::Thing.Node": {
+				Properties: map[string]spec.Property{
+					"Next": {Type: "Node", Required: true},
+				},
+			},
+		},
+	}
+
+	files, err := GenerateResources(s)
+	if err != nil {
+		t.Fatalf("GenerateResources() error: %v", err)
+	}
+	code := string(files["test/test.go"])
+	for _, want := range []string{
+		"ResourceOptions intrinsics.StringValue `json:\"ResourceOptions,omitempty\"`",
+		"ResourceOptions_ ResourceOptions `json:\"-\"`",
+		"Child intrinsics.ObjectValue[ThingChildProperty] `json:\"Child\"`",
+		"type ThingChildProperty struct {",
+		"type ThingChild struct {",
+		"Next intrinsics.ObjectValue[ThingNode] `json:\"Next\"`",
+		"Settings intrinsics.ObjectValue[ThingSettings] `json:\"Settings,omitempty\"`",
+		"Gadget intrinsics.ObjectValue[ThingThingamajig] `json:\"Gadget,omitempty\"`",
+		"Weight intrinsics.DoubleValue `json:\"Weight,omitempty\"`",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("test/test.go missing %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateResources_BrokenSpec(t *testing.T) {
+	s := &spec.Spec{
+		ResourceTypes: map[string]spec.ResourceType{
+			"AWS::Test::Thing": {
+				Properties: map[string]spec.Property{
+					"Config": {Type: "Missing"},
+				},
+			},
+		},
+	}
+
+	_, err := GenerateResources(s)
+	var checkErr *spec.CheckError
+	if !errors.As(err, &checkErr) {
+		t.Fatalf("expected *spec.CheckError, got %v", err)
+	}
+}
+
+// fakeProvider serves a single resource type without building a spec.
+type fakeProvider struct{}
+
+func (fakeProvider) Version() string                           { return "1.2.3" }
+func (fakeProvider) ResourceTypeNames() []string               { return []string{"AWS::Fake::Widget"} }
+func (fakeProvider) PropertyTypeNames() []string               { return nil }
+func (fakeProvider) GetPropertyType(string) *spec.PropertyType { return nil }
+func (fakeProvider) GetResourceType(name string) *spec.ResourceType {
+	if name != "AWS::Fake::Widget" {
+		return nil
+	}
+	return &spec.ResourceType{Properties: map[string]spec.Property{
+		"Size": {PrimitiveType: "Integer", Required: true},
+	}}
+}
+
+func TestGenerateResources_Provider(t *testing.T) {
+	files, err := GenerateResources(fakeProvider{})
+	if err != nil {
+		t.Fatalf("GenerateResources() error: %v", err)
+	}
+	code := string(files["fake/fake.go"])
+	for _, want := range []string{"specification 1.2.3", "type Widget struct {", "Size intrinsics.IntValue `json:\"Size\"`"} {
+		if !strings.Contains(code, want) {
+			t.Errorf("fake/fake.go missing %q\n%s", want, code)
+		}
+	}
+}
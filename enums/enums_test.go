@@ -179,6 +179,32 @@ func TestGetEnumForProperty(t *testing.T) {
 	}
 }
 
+func TestServiceForCloudFormation(t *testing.T) {
+	tests := map[string]string{
+		"Lambda":                 "lambda",
+		"S3":                     "s3",
+		"ElasticLoadBalancingV2": "elbv2",
+		"CertificateManager":     "acm",
+	}
+	for service, want := range tests {
+		if got := enums.ServiceForCloudFormation(service); got != want {
+			t.Errorf("ServiceForCloudFormation(%q) = %q, want %q", service, got, want)
+		}
+	}
+}
+
+func TestCloudFormationServices_Exist(t *testing.T) {
+	services := map[string]bool{}
+	for _, s := range enums.Services() {
+		services[s] = true
+	}
+	for cfn, service := range enums.CloudFormationServices {
+		if !services[service] {
+			t.Errorf("CloudFormationServices[%q] = %q, not in Services()", cfn, service)
+		}
+	}
+}
+
 func TestDynamoDBEnums(t *testing.T) {
 	// BillingMode
 	if !enums.IsValidValue("dynamodb", "BillingMode", "PROVISIONED") {
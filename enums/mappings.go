@@ -1,5 +1,7 @@
 package enums
 
+import "strings"
+
 // PropertyEnumMapping maps (service, propertyName) to enum type name.
 // This helps importers and linters know which properties accept enum values.
 // Property names are in PascalCase as used in CloudFormation.
@@ -65,3 +67,35 @@ func GetEnumForProperty(service, propertyName string) string {
 	}
 	return ""
 }
+
+// CloudFormationServices maps CloudFormation service names (the middle part
+// of "AWS::Service::Type") to enums service names, for services whose enums
+// name is not simply the lowercased CloudFormation name.
+var CloudFormationServices = map[string]string{
+	"ACMPCA":                 "acm-pca",
+	"ApplicationAutoScaling": "application-autoscaling",
+	"CertificateManager":     "acm",
+	"CodeStarConnections":    "codestar-connections",
+	"Cognito":                "cognito-idp",
+	"ElasticLoadBalancingV2": "elbv2",
+	"Elasticsearch":          "es",
+	"KinesisFirehose":        "firehose",
+	"MSK":                    "kafka",
+	"NetworkFirewall":        "network-firewall",
+	"OpenSearchService":      "opensearch",
+	"ResourceExplorer2":      "resource-explorer-2",
+	"ResourceGroups":         "resource-groups",
+	"SSMContacts":            "ssm-contacts",
+	"SSMIncidents":           "ssm-incidents",
+	"VpcLattice":             "vpc-lattice",
+}
+
+// ServiceForCloudFormation returns the enums service name for a
+// CloudFormation service name, e.g. "elbv2" for "ElasticLoadBalancingV2"
+// and "lambda" for "Lambda".
+func ServiceForCloudFormation(service string) string {
+	if s, ok := CloudFormationServices[service]; ok {
+		return s
+	}
+	return strings.ToLower(service)
+}
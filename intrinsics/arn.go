@@ -0,0 +1,329 @@
+package intrinsics
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ARN is an Amazon Resource Name:
+//
+//	arn:partition:service:region:account:resource
+//
+// Partition, Region, Account and Resource hold a string, an intrinsic such
+// as AWS_PARTITION or Ref{"BucketName"}, or a []any of such parts that are
+// concatenated. A nil Region or Account is left empty, as in S3 bucket
+// and IAM ARNs.
+type ARN struct {
+	Partition any
+	Service   string
+	Region    any
+	Account   any
+	Resource  any
+}
+
+// NewARN returns an ARN in the stack's partition, region and account.
+// Resource parts are concatenated.
+//
+// Example:
+//
+//	NewARN("sqs", Ref{"Queue"}) → arn:${AWS::Partition}:sqs:${AWS::Region}:${AWS::AccountId}:${Queue}
+func NewARN(service string, resource ...any) ARN {
+	return ARN{Partition: AWS_PARTITION, Service: service, Region: AWS_REGION, Account: AWS_ACCOUNT_ID, Resource: parts(resource)}
+}
+
+// GlobalARN returns an ARN in the stack's partition and account with no
+// region, as used by global services such as IAM.
+func GlobalARN(service string, resource ...any) ARN {
+	return ARN{Partition: AWS_PARTITION, Service: service, Account: AWS_ACCOUNT_ID, Resource: parts(resource)}
+}
+
+// S3BucketARN returns the ARN of an S3 bucket, which has no region or account.
+func S3BucketARN(bucket any) ARN {
+	return ARN{Partition: AWS_PARTITION, Service: "s3", Resource: bucket}
+}
+
+// S3ObjectARN returns the ARN of objects in an S3 bucket. key may contain
+// wildcards, as in "logs/*".
+func S3ObjectARN(bucket, key any) ARN {
+	return ARN{Partition: AWS_PARTITION, Service: "s3", Resource: []any{bucket, "/", key}}
+}
+
+// IAMRoleARN returns the ARN of an IAM role in the stack's account.
+func IAMRoleARN(name any) ARN {
+	return GlobalARN("iam", "role/", name)
+}
+
+// IAMPolicyARN returns the ARN of a customer managed IAM policy in the
+// stack's account.
+func IAMPolicyARN(name any) ARN {
+	return GlobalARN("iam", "policy/", name)
+}
+
+// AWSManagedPolicyARN returns the ARN of an AWS managed policy, such as
+// "service-role/AWSLambdaBasicExecutionRole".
+func AWSManagedPolicyARN(name string) ARN {
+	return ARN{Partition: AWS_PARTITION, Service: "iam", Account: "aws", Resource: "policy/" + name}
+}
+
+// LambdaFunctionARN returns the ARN of a Lambda function.
+func LambdaFunctionARN(name any) ARN {
+	return NewARN("lambda", "function:", name)
+}
+
+// SNSTopicARN returns the ARN of an SNS topic.
+func SNSTopicARN(name any) ARN {
+	return NewARN("sns", name)
+}
+
+// SQSQueueARN returns the ARN of an SQS queue.
+func SQSQueueARN(name any) ARN {
+	return NewARN("sqs", name)
+}
+
+// DynamoDBTableARN returns the ARN of a DynamoDB table.
+func DynamoDBTableARN(name any) ARN {
+	return NewARN("dynamodb", "table/", name)
+}
+
+// LogGroupARN returns the ARN of a CloudWatch Logs log group.
+func LogGroupARN(name any) ARN {
+	return NewARN("logs", "log-group:", name)
+}
+
+// parts returns a single part as is and several as a []any.
+func parts(values []any) any {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return values
+}
+
+// Build returns the ARN as a template value: a String if every part is a
+// literal, a Sub if the intrinsics are Refs and GetAtts, and a Join otherwise.
+func (a ARN) Build() StringValue {
+	tokens := mergeTokens([]any{"arn:", a.Partition, ":" + a.Service + ":", a.Region, ":", a.Account, ":", a.Resource})
+
+	if len(tokens) == 1 {
+		if s, ok := tokens[0].(string); ok {
+			return String(s)
+		}
+	}
+
+	var b strings.Builder
+	for _, token := range tokens {
+		switch t := token.(type) {
+		case string:
+			b.WriteString(strings.ReplaceAll(t, "${", "${!"))
+		case Ref:
+			b.WriteString("${" + t.LogicalName + "}")
+		case GetAtt:
+			b.WriteString("${" + t.LogicalName + "." + t.Attribute + "}")
+		default:
+			return Join{Delimiter: "", Values: tokens}
+		}
+	}
+	return Sub{String: b.String()}
+}
+
+// mergeTokens flattens parts and joins adjacent strings.
+func mergeTokens(values []any) []any {
+	var tokens []any
+	var add func(v any)
+	add = func(v any) {
+		switch v := v.(type) {
+		case nil:
+		case []any:
+			for _, item := range v {
+				add(item)
+			}
+		case String:
+			add(string(v))
+		case string:
+			if v == "" {
+				return
+			}
+			if n := len(tokens); n > 0 {
+				if s, ok := tokens[n-1].(string); ok {
+					tokens[n-1] = s + v
+					return
+				}
+			}
+			tokens = append(tokens, v)
+		default:
+			tokens = append(tokens, v)
+		}
+	}
+	for _, v := range values {
+		add(v)
+	}
+	return tokens
+}
+
+// String returns the ARN in Fn::Sub syntax, or in Fn::Join syntax if it
+// cannot be expressed as a Sub.
+func (a ARN) String() string {
+	switch v := a.Build().(type) {
+	case String:
+		return string(v)
+	case Sub:
+		return v.String
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// MarshalJSON serializes the ARN as built by Build.
+func (a ARN) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.Build())
+}
+
+// MarshalYAML writes the ARN as built by Build.
+func (a ARN) MarshalYAML() (any, error) {
+	return EncodeNode(a.Build(), ShortFormFallbackOuter)
+}
+
+func (ARN) stringValue() {}
+
+// HardcodedPartition reports whether the partition is a literal such as
+// "aws" instead of AWS_PARTITION, which breaks the template in China and
+// GovCloud regions.
+func (a ARN) HardcodedPartition() bool {
+	s, ok := a.Partition.(string)
+	return ok && s != ""
+}
+
+// HardcodedRegion reports whether the region is a literal.
+func (a ARN) HardcodedRegion() bool {
+	s, ok := a.Region.(string)
+	return ok && s != ""
+}
+
+// HardcodedAccount reports whether the account is a literal account ID.
+// The "aws" account of AWS managed policies is not hard-coded.
+func (a ARN) HardcodedAccount() bool {
+	s, ok := a.Account.(string)
+	return ok && accountIDPattern.MatchString(s)
+}
+
+var accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+
+// ParseARN parses an ARN from a template literal. Besides plain ARNs it
+// accepts Fn::Sub strings, so ${AWS::Partition} becomes AWS_PARTITION,
+// ${Name} a Ref and ${Name.Attr} a GetAtt.
+//
+// Example:
+//
+//	ParseARN("arn:${AWS::Partition}:s3:::${Bucket}/*")
+//	→ ARN{Partition: AWS_PARTITION, Service: "s3", Resource: []any{Ref{"Bucket"}, "/*"}}
+func ParseARN(s string) (ARN, error) {
+	return ParseARNValue(s)
+}
+
+// ParseARNValue parses an ARN from a string, a Sub without a variable map
+// or a Join, as found in templates.
+func ParseARNValue(v any) (ARN, error) {
+	var tokens []any
+	switch v := v.(type) {
+	case string:
+		tokens = subTokens(v)
+	case String:
+		tokens = subTokens(string(v))
+	case Sub:
+		tokens = subTokens(v.String)
+	case Join:
+		for i, value := range v.Values {
+			if i > 0 && v.Delimiter != "" {
+				tokens = append(tokens, v.Delimiter)
+			}
+			tokens = append(tokens, value)
+		}
+	default:
+		return ARN{}, fmt.Errorf("intrinsics: cannot parse ARN from %T", v)
+	}
+
+	// Split on the first five colons of the literal text.
+	var segments [][]any
+	current := []any{}
+	for _, token := range tokens {
+		s, ok := token.(string)
+		if !ok {
+			current = append(current, token)
+			continue
+		}
+		for len(segments) < 5 {
+			before, after, found := strings.Cut(s, ":")
+			if !found {
+				break
+			}
+			current = append(current, before)
+			segments = append(segments, current)
+			current = []any{}
+			s = after
+		}
+		current = append(current, s)
+	}
+	segments = append(segments, current)
+
+	if len(segments) != 6 || segment(segments[0]) != "arn" {
+		return ARN{}, fmt.Errorf("intrinsics: %s is not an ARN", describeARN(v))
+	}
+	service, ok := segment(segments[2]).(string)
+	if !ok || service == "" {
+		return ARN{}, fmt.Errorf("intrinsics: %s has no literal service", describeARN(v))
+	}
+	return ARN{
+		Partition: segment(segments[1]),
+		Service:   service,
+		Region:    segment(segments[3]),
+		Account:   segment(segments[4]),
+		Resource:  segment(segments[5]),
+	}, nil
+}
+
+// segment returns the value of ARN segment parts: nil if empty, the part
+// itself if there is one, and a []any otherwise.
+func segment(tokens []any) any {
+	merged := mergeTokens(tokens)
+	switch len(merged) {
+	case 0:
+		return nil
+	case 1:
+		return merged[0]
+	}
+	return merged
+}
+
+func describeARN(v any) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// subVariable matches ${Name} and ${Name.Attr} in Fn::Sub strings.
+var subVariable = regexp.MustCompile(`\$\{([^}!][^}]*)\}`)
+
+// subTokens splits an Fn::Sub string into literal text, Refs and GetAtts.
+func subTokens(s string) []any {
+	var tokens []any
+	last := 0
+	for _, m := range subVariable.FindAllStringSubmatchIndex(s, -1) {
+		tokens = append(tokens, unescapeSub(s[last:m[0]]))
+		name := s[m[2]:m[3]]
+		if resource, attr, found := strings.Cut(name, "."); found && !strings.HasPrefix(name, "AWS::") {
+			tokens = append(tokens, GetAtt{LogicalName: resource, Attribute: attr})
+		} else {
+			tokens = append(tokens, Ref{LogicalName: name})
+		}
+		last = m[1]
+	}
+	return append(tokens, unescapeSub(s[last:]))
+}
+
+func unescapeSub(s string) string {
+	return strings.ReplaceAll(s, "${!", "${")
+}
@@ -0,0 +1,137 @@
+package intrinsics_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+func TestARN_Build(t *testing.T) {
+	tests := []struct {
+		name string
+		arn  intrinsics.ARN
+		want string
+	}{
+		{
+			name: "s3_bucket",
+			arn:  intrinsics.S3BucketARN(intrinsics.Ref{LogicalName: "Bucket"}),
+			want: `{"Fn::Sub":"arn:${AWS::Partition}:s3:::${Bucket}"}`,
+		},
+		{
+			name: "s3_objects",
+			arn:  intrinsics.S3ObjectARN("my-bucket", "logs/*"),
+			want: `{"Fn::Sub":"arn:${AWS::Partition}:s3:::my-bucket/logs/*"}`,
+		},
+		{
+			name: "iam_role",
+			arn:  intrinsics.IAMRoleARN(intrinsics.Ref{LogicalName: "RoleName"}),
+			want: `{"Fn::Sub":"arn:${AWS::Partition}:iam::${AWS::AccountId}:role/${RoleName}"}`,
+		},
+		{
+			name: "managed_policy",
+			arn:  intrinsics.AWSManagedPolicyARN("service-role/AWSLambdaBasicExecutionRole"),
+			want: `{"Fn::Sub":"arn:${AWS::Partition}:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"}`,
+		},
+		{
+			name: "lambda_getatt",
+			arn:  intrinsics.LambdaFunctionARN(intrinsics.GetAtt{LogicalName: "Fn", Attribute: "Name"}),
+			want: `{"Fn::Sub":"arn:${AWS::Partition}:lambda:${AWS::Region}:${AWS::AccountId}:function:${Fn.Name}"}`,
+		},
+		{
+			name: "join_for_other_intrinsics",
+			arn:  intrinsics.SQSQueueARN(intrinsics.ImportValue{ExportName: "queue"}),
+			want: `{"Fn::Join":["",["arn:",{"Ref":"AWS::Partition"},":sqs:",{"Ref":"AWS::Region"},":",{"Ref":"AWS::AccountId"},":",{"Fn::ImportValue":"queue"}]]}`,
+		},
+		{
+			name: "literal",
+			arn:  intrinsics.ARN{Partition: "aws", Service: "sns", Region: "us-east-1", Account: "123456789012", Resource: "topic"},
+			want: `"arn:aws:sns:us-east-1:123456789012:topic"`,
+		},
+		{
+			name: "escaped_literal",
+			arn:  intrinsics.S3ObjectARN("b", "${literal}"),
+			want: `{"Fn::Sub":"arn:${AWS::Partition}:s3:::b/${!literal}"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mustMarshal(t, tt.arn)
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseARN(t *testing.T) {
+	tests := []struct {
+		input string
+		want  intrinsics.ARN
+	}{
+		{
+			input: "arn:aws:s3:::my-bucket/*",
+			want:  intrinsics.ARN{Partition: "aws", Service: "s3", Resource: "my-bucket/*"},
+		},
+		{
+			input: "arn:aws:lambda:us-east-1:123456789012:function:my-fn",
+			want:  intrinsics.ARN{Partition: "aws", Service: "lambda", Region: "us-east-1", Account: "123456789012", Resource: "function:my-fn"},
+		},
+		{
+			input: "arn:${AWS::Partition}:iam::${AWS::AccountId}:role/${RoleName}",
+			want:  intrinsics.IAMRoleARN(intrinsics.Ref{LogicalName: "RoleName"}),
+		},
+		{
+			input: "arn:${AWS::Partition}:logs:${AWS::Region}:${AWS::AccountId}:log-group:${Fn.Name}",
+			want:  intrinsics.LogGroupARN(intrinsics.GetAtt{LogicalName: "Fn", Attribute: "Name"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := intrinsics.ParseARN(tt.input)
+			if err != nil {
+				t.Fatalf("ParseARN failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+			if got.String() != tt.input {
+				t.Errorf("String() = %s, want %s", got.String(), tt.input)
+			}
+		})
+	}
+
+	for _, input := range []string{"arn:aws:s3", "urn:aws:s3:::b", "arn:aws::us-east-1:1:x"} {
+		if _, err := intrinsics.ParseARN(input); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestParseARNValue_Join(t *testing.T) {
+	join := intrinsics.Join{Delimiter: "", Values: []any{"arn:", intrinsics.AWS_PARTITION, ":sqs:", intrinsics.AWS_REGION, ":", intrinsics.AWS_ACCOUNT_ID, ":", intrinsics.ImportValue{ExportName: "q"}}}
+	got, err := intrinsics.ParseARNValue(join)
+	if err != nil {
+		t.Fatalf("ParseARNValue failed: %v", err)
+	}
+	if want := intrinsics.SQSQueueARN(intrinsics.ImportValue{ExportName: "q"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestARN_Hardcoded(t *testing.T) {
+	literal, err := intrinsics.ParseARN("arn:aws:iam::123456789012:role/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !literal.HardcodedPartition() || !literal.HardcodedAccount() || literal.HardcodedRegion() {
+		t.Errorf("got partition %v, account %v, region %v", literal.HardcodedPartition(), literal.HardcodedAccount(), literal.HardcodedRegion())
+	}
+
+	managed := intrinsics.AWSManagedPolicyARN("ReadOnlyAccess")
+	if managed.HardcodedPartition() || managed.HardcodedAccount() {
+		t.Errorf("managed policy ARN reported as hard-coded: %s", managed)
+	}
+}
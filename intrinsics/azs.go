@@ -0,0 +1,72 @@
+package intrinsics
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// AZProvider returns the availability zones of a region, as Fn::GetAZs
+// does. Implementations may return a static table or query an account.
+type AZProvider interface {
+	AZs(region string) ([]string, error)
+}
+
+// StaticAZs is an AZProvider backed by a map of regions to zones.
+type StaticAZs map[string][]string
+
+// AZs returns the zones of the region, or an error if it is not in the map.
+func (s StaticAZs) AZs(region string) ([]string, error) {
+	zones, ok := s[region]
+	if !ok {
+		return nil, fmt.Errorf("intrinsics: no availability zones for region %q", region)
+	}
+	return zones, nil
+}
+
+// Regions returns the regions of the map, sorted.
+func (s StaticAZs) Regions() []string {
+	regions := make([]string, 0, len(s))
+	for region := range s {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+//go:embed azs/azs.json
+var azsJSON []byte
+
+var bundledAZs = func() StaticAZs {
+	var azs StaticAZs
+	if err := json.Unmarshal(azsJSON, &azs); err != nil {
+		panic(fmt.Sprintf("intrinsics: invalid bundled availability zones: %v", err))
+	}
+	return azs
+}()
+
+// BundledAZs returns the bundled table of availability zones per region.
+// Zone names are the defaults; which physical zones an account sees, and
+// whether opt-in zones are included, varies by account, so tests that
+// depend on exact names should use their own StaticAZs.
+func BundledAZs() StaticAZs {
+	return bundledAZs
+}
+
+// Zones evaluates the GetAZs with a provider. An empty Region is the
+// stack's region, currentRegion.
+//
+// Example:
+//
+//	GetAZs{}.Zones(BundledAZs(), "us-east-2") // ["us-east-2a", "us-east-2b", "us-east-2c"]
+func (g GetAZs) Zones(p AZProvider, currentRegion string) ([]string, error) {
+	region := g.Region
+	if region == "" {
+		region = currentRegion
+	}
+	if region == "" {
+		return nil, fmt.Errorf("intrinsics: Fn::GetAZs needs a region")
+	}
+	return p.AZs(region)
+}
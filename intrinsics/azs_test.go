@@ -0,0 +1,67 @@
+package intrinsics_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+func TestBundledAZs(t *testing.T) {
+	azs := intrinsics.BundledAZs()
+	for _, region := range []string{"us-east-1", "eu-west-1", "ap-southeast-2", "cn-north-1", "us-gov-west-1"} {
+		zones, err := azs.AZs(region)
+		if err != nil {
+			t.Errorf("AZs(%s) failed: %v", region, err)
+			continue
+		}
+		if len(zones) < 2 {
+			t.Errorf("AZs(%s) returned %v, want at least 2 zones", region, zones)
+		}
+		for _, zone := range zones {
+			if !strings.HasPrefix(zone, region) {
+				t.Errorf("zone %s is not in region %s", zone, region)
+			}
+		}
+	}
+
+	regions := azs.Regions()
+	if len(regions) != len(azs) || regions[0] > regions[len(regions)-1] {
+		t.Errorf("Regions() returned %v", regions)
+	}
+}
+
+func TestStaticAZs_UnknownRegion(t *testing.T) {
+	_, err := intrinsics.StaticAZs{"us-east-1": {"us-east-1a"}}.AZs("mars-1")
+	if err == nil || !strings.Contains(err.Error(), `"mars-1"`) {
+		t.Errorf("got %v, want error naming mars-1", err)
+	}
+}
+
+func TestGetAZs_Zones(t *testing.T) {
+	azs := intrinsics.StaticAZs{
+		"us-east-1": {"us-east-1a", "us-east-1b"},
+		"eu-west-1": {"eu-west-1a"},
+	}
+
+	got, err := intrinsics.GetAZs{}.Zones(azs, "us-east-1")
+	if err != nil {
+		t.Fatalf("Zones failed: %v", err)
+	}
+	if want := []string{"us-east-1a", "us-east-1b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got, err = intrinsics.GetAZs{Region: "eu-west-1"}.Zones(azs, "us-east-1")
+	if err != nil {
+		t.Fatalf("Zones failed: %v", err)
+	}
+	if want := []string{"eu-west-1a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := (intrinsics.GetAZs{}).Zones(azs, ""); err == nil {
+		t.Error("expected error without a region")
+	}
+}
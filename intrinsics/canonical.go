@@ -0,0 +1,210 @@
+package intrinsics
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Equal reports whether two values are equal after Canonicalize, so that
+// expressions written differently but evaluating the same compare equal.
+//
+// Example:
+//
+//	Equal(GetAtt{"Role", "Arn"}, map[string]any{"Fn::GetAtt": "Role.Arn"}) // true
+//	Equal(Sub{"logs"}, "logs")                                             // true
+func Equal(a, b any) bool {
+	return reflect.DeepEqual(Canonicalize(a), Canonicalize(b))
+}
+
+// Canonicalize returns the canonical form of a value, with intrinsics
+// nested at any depth of maps, slices and intrinsic arguments rewritten:
+//
+//   - long-form maps are decoded as by Decode
+//   - String, Int, Double and Bool become string, int, float64 and bool, and
+//     whole float64s int
+//   - List, ListOf and ARN become the values they encode as
+//   - GetAttDynamic with a literal attribute becomes GetAtt
+//   - Sub without variables becomes its literal string, and Sub of a single
+//     variable such as "${Name}" or "${Name.Attr}" a Ref or GetAtt
+//   - SubWithMap without variables becomes Sub
+//   - Join merges adjacent literals, and a Join of no values or of a single
+//     value becomes "" or that value
+//   - Not of a one-element list becomes Not of the element
+//
+// The canonical form encodes to an equivalent template value.
+func Canonicalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, value := range v {
+			m[key] = Canonicalize(value)
+		}
+		if len(m) == 1 {
+			for key, value := range m {
+				if fn, ok, err := decodeFunction(key, value); ok && err == nil {
+					return Canonicalize(fn)
+				}
+			}
+		}
+		return m
+	case []any:
+		return canonicalList(v)
+
+	case String:
+		return string(v)
+	case Int:
+		return int(v)
+	case Double:
+		return Canonicalize(float64(v))
+	case Bool:
+		return bool(v)
+	case int64:
+		if v >= math.MinInt && v <= math.MaxInt {
+			return int(v)
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt && v <= math.MaxInt {
+			return int(v)
+		}
+	case json.Number:
+		if d, err := Decode(v); err == nil {
+			return Canonicalize(d)
+		}
+	case interface{ Items() []any }:
+		return canonicalList(v.Items())
+	case interface{ Wrapped() any }:
+		return Canonicalize(v.Wrapped())
+	case ARN:
+		return Canonicalize(v.Build())
+
+	case GetAttDynamic:
+		attribute := Canonicalize(v.Attribute)
+		if s, ok := attribute.(string); ok {
+			return GetAtt{LogicalName: v.LogicalName, Attribute: s}
+		}
+		return GetAttDynamic{LogicalName: v.LogicalName, Attribute: attribute}
+	case Sub:
+		return canonicalSub(v.String)
+	case SubWithMap:
+		if len(v.Variables) == 0 {
+			return canonicalSub(v.String)
+		}
+		return SubWithMap{String: v.String, Variables: canonicalMap(v.Variables)}
+	case Join:
+		return canonicalJoin(v)
+	case Not:
+		condition := Canonicalize(v.Condition)
+		if list, ok := condition.([]any); ok && len(list) == 1 {
+			condition = list[0]
+		}
+		return Not{Condition: condition}
+
+	case Select:
+		return Select{Index: v.Index, List: Canonicalize(v.List)}
+	case If:
+		return If{Condition: v.Condition, ValueIfTrue: Canonicalize(v.ValueIfTrue), ValueIfFalse: Canonicalize(v.ValueIfFalse)}
+	case Equals:
+		return Equals{Value1: Canonicalize(v.Value1), Value2: Canonicalize(v.Value2)}
+	case And:
+		return And{Conditions: canonicalList(v.Conditions)}
+	case Or:
+		return Or{Conditions: canonicalList(v.Conditions)}
+	case Base64:
+		return Base64{Value: Canonicalize(v.Value)}
+	case ImportValue:
+		return ImportValue{ExportName: Canonicalize(v.ExportName)}
+	case FindInMap:
+		return FindInMap{MapName: v.MapName, TopKey: Canonicalize(v.TopKey), SecondKey: Canonicalize(v.SecondKey)}
+	case FindInMapWithDefault:
+		return FindInMapWithDefault{MapName: v.MapName, TopKey: Canonicalize(v.TopKey), SecondKey: Canonicalize(v.SecondKey), DefaultValue: Canonicalize(v.DefaultValue)}
+	case Split:
+		return Split{Delimiter: v.Delimiter, Source: Canonicalize(v.Source)}
+	case Cidr:
+		return Cidr{IPBlock: Canonicalize(v.IPBlock), Count: Canonicalize(v.Count), CidrBits: Canonicalize(v.CidrBits)}
+	case Length:
+		return Length{Value: Canonicalize(v.Value)}
+	case ToJsonString:
+		return ToJsonString{Value: Canonicalize(v.Value)}
+	case ForEach:
+		return ForEach{LoopName: v.LoopName, Identifier: v.Identifier, Collection: Canonicalize(v.Collection), Output: canonicalMap(v.Output)}
+	case Tag:
+		return Tag{Key: v.Key, Value: Canonicalize(v.Value)}
+	case Transform:
+		return Transform{Name: v.Name, Parameters: canonicalMap(v.Parameters)}
+	case Output:
+		return Output{Value: Canonicalize(v.Value), Description: v.Description, ExportName: Canonicalize(v.ExportName), Condition: v.Condition}
+	case Rule:
+		assertions := make([]Assertion, len(v.Assertions))
+		for i, a := range v.Assertions {
+			assertions[i] = Assertion{Assert: Canonicalize(a.Assert), AssertDescription: a.AssertDescription}
+		}
+		return Rule{RuleCondition: Canonicalize(v.RuleCondition), Assertions: assertions}
+	case Contains:
+		return Contains{List: Canonicalize(v.List), Value: Canonicalize(v.Value)}
+	case EachMemberEquals:
+		return EachMemberEquals{List: Canonicalize(v.List), Value: Canonicalize(v.Value)}
+	case EachMemberIn:
+		return EachMemberIn{StringsToCheck: Canonicalize(v.StringsToCheck), StringsToMatch: Canonicalize(v.StringsToMatch)}
+	}
+	return v
+}
+
+func canonicalList(values []any) []any {
+	if values == nil {
+		return nil
+	}
+	list := make([]any, len(values))
+	for i, v := range values {
+		list[i] = Canonicalize(v)
+	}
+	return list
+}
+
+func canonicalMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	canonical := make(map[string]any, len(m))
+	for key, value := range m {
+		canonical[key] = Canonicalize(value)
+	}
+	return canonical
+}
+
+// canonicalSub returns a Sub string as a literal, a Ref or GetAtt, or a Sub.
+func canonicalSub(s string) any {
+	matches := subVariable.FindAllStringSubmatchIndex(s, -1)
+	switch {
+	case len(matches) == 0:
+		return unescapeSub(s)
+	case len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s):
+		return subTokens(s)[1]
+	}
+	return Sub{String: s}
+}
+
+// canonicalJoin merges adjacent literal values of a Join, which is
+// equivalent to joining them with the delimiter first.
+func canonicalJoin(j Join) any {
+	var values []any
+	for _, v := range canonicalList(j.Values) {
+		if s, ok := v.(string); ok {
+			if n := len(values); n > 0 {
+				if prev, ok := values[n-1].(string); ok {
+					values[n-1] = strings.Join([]string{prev, s}, j.Delimiter)
+					continue
+				}
+			}
+		}
+		values = append(values, v)
+	}
+	switch len(values) {
+	case 0:
+		return ""
+	case 1:
+		return values[0]
+	}
+	return Join{Delimiter: j.Delimiter, Values: values}
+}
@@ -0,0 +1,111 @@
+package intrinsics_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b any
+	}{
+		{"getatt_forms", intrinsics.GetAtt{LogicalName: "Role", Attribute: "Arn"}, map[string]any{"Fn::GetAtt": "Role.Arn"}},
+		{"getatt_list", map[string]any{"Fn::GetAtt": []any{"Role", "Arn"}}, map[string]any{"Fn::GetAtt": "Role.Arn"}},
+		{"getatt_dynamic_literal", intrinsics.GetAttDynamic{LogicalName: "Role", Attribute: "Arn"}, intrinsics.GetAtt{LogicalName: "Role", Attribute: "Arn"}},
+		{"sub_literal", intrinsics.Sub{String: "my-bucket"}, "my-bucket"},
+		{"sub_escaped", intrinsics.Sub{String: "${!Literal}"}, "${Literal}"},
+		{"sub_ref", intrinsics.Sub{String: "${AWS::Region}"}, intrinsics.AWS_REGION},
+		{"sub_getatt", intrinsics.Sub{String: "${Role.Arn}"}, intrinsics.GetAtt{LogicalName: "Role", Attribute: "Arn"}},
+		{"sub_empty_map", intrinsics.SubWithMap{String: "${Name}-x", Variables: map[string]any{}}, intrinsics.Sub{String: "${Name}-x"}},
+		{"join_single", intrinsics.Join{Delimiter: ",", Values: []any{intrinsics.Ref{LogicalName: "A"}}}, intrinsics.Ref{LogicalName: "A"}},
+		{"join_empty", intrinsics.Join{Delimiter: ",", Values: []any{}}, ""},
+		{"join_literals", intrinsics.Join{Delimiter: "-", Values: []any{"a", intrinsics.String("b")}}, "a-b"},
+		{
+			"join_adjacent_literals",
+			intrinsics.Join{Delimiter: ":", Values: []any{"arn", "aws", intrinsics.Ref{LogicalName: "X"}}},
+			intrinsics.Join{Delimiter: ":", Values: []any{"arn:aws", intrinsics.Ref{LogicalName: "X"}}},
+		},
+		{"not_list", intrinsics.Not{Condition: []any{intrinsics.Condition{Name: "IsProd"}}}, intrinsics.Not{Condition: intrinsics.Condition{Name: "IsProd"}}},
+		{"not_long_form", map[string]any{"Fn::Not": []any{map[string]any{"Condition": "IsProd"}}}, intrinsics.NotOf(intrinsics.Condition{Name: "IsProd"})},
+		{"typed_literals", intrinsics.Strings("a", "b"), []any{"a", "b"}},
+		{"numbers", []any{intrinsics.Int(3), 3.0, int64(3)}, []any{3, 3, 3}},
+		{"doubles", []any{intrinsics.Double(2), intrinsics.Double(0.5)}, []any{2, 0.5}},
+		{"list_of", intrinsics.ListOf[intrinsics.StringValue]{Value: intrinsics.Ref{LogicalName: "Subnets"}}, intrinsics.Ref{LogicalName: "Subnets"}},
+		{"arn", intrinsics.S3BucketARN(intrinsics.Ref{LogicalName: "Bucket"}), intrinsics.Sub{String: "arn:${AWS::Partition}:s3:::${Bucket}"}},
+		{
+			"nested",
+			map[string]any{"Tags": []any{intrinsics.Tag{Key: "Name", Value: intrinsics.Sub{String: "${AWS::StackName}"}}}},
+			map[string]any{"Tags": []any{intrinsics.Tag{Key: "Name", Value: intrinsics.AWS_STACK_NAME}}},
+		},
+		{
+			"nested_in_arguments",
+			intrinsics.If{Condition: "C", ValueIfTrue: intrinsics.Join{Delimiter: "", Values: []any{intrinsics.Sub{String: "x"}}}, ValueIfFalse: map[string]any{"Ref": "AWS::NoValue"}},
+			intrinsics.If{Condition: "C", ValueIfTrue: "x", ValueIfFalse: intrinsics.AWS_NO_VALUE},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !intrinsics.Equal(tt.a, tt.b) {
+				t.Errorf("expected equal:\n%#v\n%#v", intrinsics.Canonicalize(tt.a), intrinsics.Canonicalize(tt.b))
+			}
+		})
+	}
+}
+
+func TestEqual_NotEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b any
+	}{
+		{"getatt", intrinsics.GetAtt{LogicalName: "Role", Attribute: "Arn"}, intrinsics.GetAtt{LogicalName: "Role", Attribute: "RoleId"}},
+		{"sub_variables", intrinsics.Sub{String: "${Name}-x"}, "${Name}-x"},
+		{"join_delimiter", intrinsics.Join{Delimiter: ",", Values: []any{"a", intrinsics.AWS_REGION}}, intrinsics.Join{Delimiter: "-", Values: []any{"a", intrinsics.AWS_REGION}}},
+		{"join_literals", intrinsics.Join{Delimiter: "-", Values: []any{"a", "b"}}, "ab"},
+		{"string_number", "3", 3},
+		{"not", intrinsics.Not{Condition: intrinsics.Condition{Name: "A"}}, intrinsics.Condition{Name: "A"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if intrinsics.Equal(tt.a, tt.b) {
+				t.Errorf("expected not equal: %#v and %#v", tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	v := map[string]any{
+		"Fn::Join": []any{"", []any{
+			"arn:",
+			map[string]any{"Ref": "AWS::Partition"},
+			":s3:::",
+			map[string]any{"Fn::Sub": "bucket"},
+		}},
+	}
+	got := intrinsics.Canonicalize(v)
+	want := intrinsics.Join{Delimiter: "", Values: []any{"arn:", intrinsics.AWS_PARTITION, ":s3:::bucket"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	// The canonical form encodes to an equivalent value.
+	data, err := json.Marshal(intrinsics.Canonicalize(intrinsics.Not{Condition: []any{intrinsics.Condition{Name: "A"}}}))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if got, want := string(data), `{"Fn::Not":[{"Condition":"A"}]}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// Canonicalize is idempotent.
+	once := intrinsics.Canonicalize(v)
+	if twice := intrinsics.Canonicalize(once); !reflect.DeepEqual(once, twice) {
+		t.Errorf("got %#v, want %#v", twice, once)
+	}
+}
@@ -0,0 +1,86 @@
+package intrinsics
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strconv"
+)
+
+// CidrSubnets computes Fn::Cidr: count CIDR blocks carved in order from
+// the start of ipBlock, each with cidrBits host bits, so cidrBits 8 yields
+// /24 blocks from an IPv4 block and /120 blocks from an IPv6 block.
+//
+// Like CloudFormation it fails if ipBlock is not a CIDR block, count is
+// not between 1 and 256, cidrBits is not between 1 and the host bits of
+// ipBlock, or ipBlock has room for fewer than count blocks.
+//
+// Example:
+//
+//	CidrSubnets("10.0.0.0/16", 3, 8) // ["10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"]
+func CidrSubnets(ipBlock string, count, cidrBits int) ([]string, error) {
+	prefix, err := netip.ParsePrefix(ipBlock)
+	if err != nil {
+		return nil, fmt.Errorf("intrinsics: Fn::Cidr: invalid CIDR block %q", ipBlock)
+	}
+	prefix = prefix.Masked()
+	total := prefix.Addr().BitLen()
+	hostBits := total - prefix.Bits()
+
+	if count < 1 || count > 256 {
+		return nil, fmt.Errorf("intrinsics: Fn::Cidr: count must be between 1 and 256, got %d", count)
+	}
+	if cidrBits < 1 || cidrBits > hostBits {
+		return nil, fmt.Errorf("intrinsics: Fn::Cidr: cidrBits must be between 1 and %d for %s, got %d", hostBits, ipBlock, cidrBits)
+	}
+	if free := hostBits - cidrBits; free < 8 && count > 1<<free {
+		return nil, fmt.Errorf("intrinsics: Fn::Cidr: %s has room for %d blocks of /%d, not %d", ipBlock, 1<<free, total-cidrBits, count)
+	}
+
+	start := new(big.Int).SetBytes(prefix.Addr().AsSlice())
+	step := new(big.Int).Lsh(big.NewInt(1), uint(cidrBits))
+	subnets := make([]string, count)
+	buf := make([]byte, total/8)
+	for i := range subnets {
+		n := new(big.Int).Add(start, new(big.Int).Mul(step, big.NewInt(int64(i))))
+		addr, _ := netip.AddrFromSlice(n.FillBytes(buf))
+		subnets[i] = netip.PrefixFrom(addr, total-cidrBits).String()
+	}
+	return subnets, nil
+}
+
+// Subnets evaluates the Cidr if its arguments are literals: a string block
+// and integer or numeric string count and bits.
+func (c Cidr) Subnets() ([]string, error) {
+	block, ok := c.IPBlock.(string)
+	if !ok {
+		return nil, fmt.Errorf("intrinsics: Fn::Cidr: IP block %s is not a literal", describeNode(c.IPBlock))
+	}
+	count, err := literalInt("count", c.Count)
+	if err != nil {
+		return nil, err
+	}
+	bits, err := literalInt("cidrBits", c.CidrBits)
+	if err != nil {
+		return nil, err
+	}
+	return CidrSubnets(block, count, bits)
+}
+
+func literalInt(name string, v any) (int, error) {
+	switch v := v.(type) {
+	case int:
+		return v, nil
+	case Int:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("intrinsics: Fn::Cidr: %s %s is not an integer literal", name, describeNode(v))
+}
@@ -0,0 +1,96 @@
+package intrinsics_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+func TestCidrSubnets(t *testing.T) {
+	tests := []struct {
+		name     string
+		block    string
+		count    int
+		cidrBits int
+		want     []string
+	}{
+		{"ipv4 /24s", "10.0.0.0/16", 3, 8, []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"}},
+		{"ipv4 small", "192.168.0.0/24", 4, 6, []string{"192.168.0.0/26", "192.168.0.64/26", "192.168.0.128/26", "192.168.0.192/26"}},
+		{"ipv4 host bits masked", "10.0.3.7/16", 2, 12, []string{"10.0.0.0/20", "10.0.16.0/20"}},
+		{"ipv4 whole block", "10.0.0.0/24", 1, 8, []string{"10.0.0.0/24"}},
+		{"ipv4 fills block", "10.0.0.0/28", 4, 2, []string{"10.0.0.0/30", "10.0.0.4/30", "10.0.0.8/30", "10.0.0.12/30"}},
+		{"ipv6 /64s", "2001:db8::/56", 3, 64, []string{"2001:db8::/64", "2001:db8:0:1::/64", "2001:db8:0:2::/64"}},
+		{"ipv6 /120s", "2001:db8::/64", 2, 8, []string{"2001:db8::/120", "2001:db8::100/120"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := intrinsics.CidrSubnets(tt.block, tt.count, tt.cidrBits)
+			if err != nil {
+				t.Fatalf("CidrSubnets failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCidrSubnets_MaxCount(t *testing.T) {
+	got, err := intrinsics.CidrSubnets("10.0.0.0/8", 256, 16)
+	if err != nil {
+		t.Fatalf("CidrSubnets failed: %v", err)
+	}
+	if len(got) != 256 || got[255] != "10.255.0.0/16" {
+		t.Errorf("got %d subnets ending in %s, want 256 ending in 10.255.0.0/16", len(got), got[len(got)-1])
+	}
+}
+
+func TestCidrSubnets_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		block    string
+		count    int
+		cidrBits int
+		want     string
+	}{
+		{"not a cidr", "10.0.0.0", 1, 8, "invalid CIDR block"},
+		{"garbage", "subnet", 1, 8, "invalid CIDR block"},
+		{"zero count", "10.0.0.0/16", 0, 8, "count must be between 1 and 256"},
+		{"count too high", "10.0.0.0/8", 257, 8, "count must be between 1 and 256"},
+		{"zero bits", "10.0.0.0/16", 1, 0, "cidrBits must be between 1 and 16"},
+		{"bits above host bits", "10.0.0.0/24", 1, 9, "cidrBits must be between 1 and 8"},
+		{"too few blocks", "10.0.0.0/24", 5, 6, "room for 4 blocks of /26, not 5"},
+		{"ipv6 bits above host bits", "2001:db8::/120", 1, 9, "cidrBits must be between 1 and 8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := intrinsics.CidrSubnets(tt.block, tt.count, tt.cidrBits)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCidr_Subnets(t *testing.T) {
+	got, err := intrinsics.Cidr{IPBlock: "10.0.0.0/16", Count: "2", CidrBits: intrinsics.Int(8)}.Subnets()
+	if err != nil {
+		t.Fatalf("Subnets failed: %v", err)
+	}
+	want := []string{"10.0.0.0/24", "10.0.1.0/24"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	_, err = intrinsics.Cidr{IPBlock: intrinsics.GetAtt{LogicalName: "VPC", Attribute: "CidrBlock"}, Count: 2, CidrBits: 8}.Subnets()
+	if err == nil || !strings.Contains(err.Error(), "Fn::GetAtt") {
+		t.Errorf("got %v, want error naming Fn::GetAtt", err)
+	}
+}
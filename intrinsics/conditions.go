@@ -0,0 +1,439 @@
+package intrinsics
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Conditions is a set of named conditions, as in the Conditions section of
+// a template. Values are condition functions such as Equals, And, Or, Not
+// and Condition, or their long-form maps.
+//
+// Example:
+//
+//	conditions := Conditions{}
+//	isProd := conditions.Add("IsProd", EqualsOf(Ref{"Env"}, String("prod")))
+//	conditions.Add("IsProdInUSEast1", AndOf(isProd, EqualsOf(AWS_REGION, String("us-east-1"))))
+type Conditions map[string]any
+
+// Add sets the named condition and returns a Condition referencing it, for
+// use in other conditions and in If.
+func (c Conditions) Add(name string, condition BoolValue) Condition {
+	c[name] = condition
+	return Condition{Name: name}
+}
+
+// EqualsOf returns an Equals of two string values.
+func EqualsOf(value1, value2 StringValue) Equals {
+	return Equals{Value1: value1, Value2: value2}
+}
+
+// AndOf returns an And of conditions. CloudFormation requires 2 to 10.
+func AndOf(conditions ...BoolValue) And {
+	return And{Conditions: boolItems(conditions)}
+}
+
+// OrOf returns an Or of conditions. CloudFormation requires 2 to 10.
+func OrOf(conditions ...BoolValue) Or {
+	return Or{Conditions: boolItems(conditions)}
+}
+
+// NotOf returns a Not of a condition.
+func NotOf(condition BoolValue) Not {
+	return Not{Condition: condition}
+}
+
+func boolItems(conditions []BoolValue) []any {
+	items := make([]any, len(conditions))
+	for i, c := range conditions {
+		items[i] = c
+	}
+	return items
+}
+
+// ConditionIssueCode identifies the kind of a condition issue.
+type ConditionIssueCode string
+
+const (
+	ConditionArity       ConditionIssueCode = "Arity"       // Fn::And or Fn::Or without 2 to 10 conditions, or Fn::Not without 1
+	ConditionCycle       ConditionIssueCode = "Cycle"       // conditions reference each other in a cycle
+	ConditionUndefined   ConditionIssueCode = "Undefined"   // Condition names a condition that is not in the set
+	ConditionAlwaysTrue  ConditionIssueCode = "AlwaysTrue"  // true for every combination of allowed parameter values
+	ConditionAlwaysFalse ConditionIssueCode = "AlwaysFalse" // false for every combination of allowed parameter values
+)
+
+// ConditionIssue is a problem found by Conditions.Check. CloudFormation
+// rejects templates with Arity, Cycle and Undefined issues; AlwaysTrue and
+// AlwaysFalse conditions deploy but are likely mistakes.
+type ConditionIssue struct {
+	Condition string // name of the condition
+	Path      string // location in the condition, such as "Fn::Or[1].Fn::And"; empty for the condition itself
+	Code      ConditionIssueCode
+	Message   string
+}
+
+// String returns the issue as "IsProd.Fn::And: Arity: ...".
+func (i ConditionIssue) String() string {
+	target := i.Condition
+	if i.Path != "" {
+		target += "." + i.Path
+	}
+	return fmt.Sprintf("%s: %s: %s", target, i.Code, i.Message)
+}
+
+// maxAssignments bounds the combinations of parameter values Check
+// evaluates a condition for; conditions over more are not analyzed.
+const maxAssignments = 4096
+
+// Check reports arity errors, references to undefined conditions, cycles
+// of Condition references, and conditions that are always true or always
+// false. allowedValues maps parameter names to their AllowedValues; a
+// condition is evaluated for every combination of allowed values of the
+// parameters it compares with Equals, and other parameters and intrinsics
+// are treated as unknown. Issues are sorted by condition, path and code.
+func (c Conditions) Check(allowedValues map[string][]any) []ConditionIssue {
+	k := &conditionChecker{
+		conditions: make(map[string]any, len(c)),
+		allowed:    allowedValues,
+		refs:       make(map[string][]string),
+		params:     make(map[string][]string),
+		cyclic:     make(map[string]bool),
+	}
+	for name, v := range c {
+		if decoded, err := Decode(v); err == nil {
+			v = decoded
+		}
+		k.conditions[name] = v
+	}
+
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		k.walk(name, k.conditions[name], "")
+	}
+	k.findCycles(names)
+	for _, name := range names {
+		k.checkTruth(name)
+	}
+
+	sort.SliceStable(k.issues, func(i, j int) bool {
+		a, b := k.issues[i], k.issues[j]
+		if a.Condition != b.Condition {
+			return a.Condition < b.Condition
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Code < b.Code
+	})
+	return k.issues
+}
+
+type conditionChecker struct {
+	conditions map[string]any
+	allowed    map[string][]any
+	refs       map[string][]string // condition -> conditions it references
+	params     map[string][]string // condition -> parameters it compares
+	cyclic     map[string]bool
+	issues     []ConditionIssue
+}
+
+func (k *conditionChecker) add(name, path string, code ConditionIssueCode, format string, args ...any) {
+	k.issues = append(k.issues, ConditionIssue{
+		Condition: name,
+		Path:      path,
+		Code:      code,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// walk checks the arity of the condition functions in v and records the
+// conditions and parameters it references.
+func (k *conditionChecker) walk(name string, v any, path string) {
+	switch v := v.(type) {
+	case And:
+		k.walkOperands(name, "Fn::And", v.Conditions, path)
+	case Or:
+		k.walkOperands(name, "Fn::Or", v.Conditions, path)
+	case Not:
+		if list, ok := v.Condition.([]any); ok {
+			k.walkOperands(name, "Fn::Not", list, path)
+		} else {
+			k.walk(name, v.Condition, joinPath(path, "Fn::Not"))
+		}
+	case Condition:
+		if _, ok := k.conditions[v.Name]; !ok {
+			k.add(name, path, ConditionUndefined, "condition %q is not defined", v.Name)
+		} else if !contains(k.refs[name], v.Name) {
+			k.refs[name] = append(k.refs[name], v.Name)
+		}
+	case Equals:
+		for _, operand := range []any{v.Value1, v.Value2} {
+			if ref, ok := operand.(Ref); ok && len(k.allowed[ref.LogicalName]) > 0 && !contains(k.params[name], ref.LogicalName) {
+				k.params[name] = append(k.params[name], ref.LogicalName)
+			}
+		}
+	case map[string]any:
+		// Long forms that do not decode, such as an Fn::Not with two conditions.
+		for key, value := range v {
+			if list, ok := value.([]any); ok && (key == "Fn::And" || key == "Fn::Or" || key == "Fn::Not") {
+				k.walkOperands(name, key, list, path)
+			}
+		}
+	}
+}
+
+func (k *conditionChecker) walkOperands(name, fn string, operands []any, path string) {
+	path = joinPath(path, fn)
+	if fn == "Fn::Not" {
+		if len(operands) != 1 {
+			k.add(name, path, ConditionArity, "%s has %d conditions; it takes 1", fn, len(operands))
+		}
+	} else if len(operands) < 2 || len(operands) > 10 {
+		k.add(name, path, ConditionArity, "%s has %d conditions; CloudFormation allows 2 to 10", fn, len(operands))
+	}
+	for i, operand := range operands {
+		k.walk(name, operand, indexPath(path, i))
+	}
+}
+
+// findCycles reports each cycle of Condition references once, on its first
+// condition by name, and marks the conditions in it.
+func (k *conditionChecker) findCycles(names []string) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	reported := make(map[string]bool)
+	var stack []string
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, ref := range k.refs[name] {
+			switch state[ref] {
+			case unvisited:
+				visit(ref)
+			case visiting:
+				start := 0
+				for stack[start] != ref {
+					start++
+				}
+				cycle := rotateToMin(stack[start:])
+				key := strings.Join(cycle, " -> ")
+				if !reported[key] {
+					reported[key] = true
+					k.add(cycle[0], "", ConditionCycle, "circular condition reference: %s -> %s", key, cycle[0])
+				}
+				for _, c := range cycle {
+					k.cyclic[c] = true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+}
+
+// rotateToMin returns a copy of cycle starting at its smallest name.
+func rotateToMin(cycle []string) []string {
+	first := 0
+	for i, name := range cycle {
+		if name < cycle[first] {
+			first = i
+		}
+	}
+	return append(append([]string{}, cycle[first:]...), cycle[:first]...)
+}
+
+// checkTruth evaluates the condition for every combination of allowed
+// values of the parameters it depends on.
+func (k *conditionChecker) checkTruth(name string) {
+	if k.cyclic[name] {
+		return
+	}
+	params := k.dependentParams(name, make(map[string]bool))
+	sort.Strings(params)
+
+	total := 1
+	for _, p := range params {
+		total *= len(k.allowed[p])
+		if total > maxAssignments {
+			return
+		}
+	}
+
+	seen := make(map[truth]bool)
+	assignment := make(map[string]any, len(params))
+	for i := 0; i < total; i++ {
+		n := i
+		for _, p := range params {
+			values := k.allowed[p]
+			assignment[p] = values[n%len(values)]
+			n /= len(values)
+		}
+		result := k.eval(k.conditions[name], assignment, map[string]bool{name: true})
+		if result == truthUnknown {
+			return
+		}
+		seen[result] = true
+		if len(seen) > 1 {
+			return
+		}
+	}
+
+	var qualifier string
+	if len(params) > 0 {
+		qualifier = fmt.Sprintf(" for all allowed values of %s", strings.Join(params, ", "))
+	}
+	if seen[truthTrue] {
+		k.add(name, "", ConditionAlwaysTrue, "condition is always true%s", qualifier)
+	} else {
+		k.add(name, "", ConditionAlwaysFalse, "condition is always false%s", qualifier)
+	}
+}
+
+// dependentParams returns the parameters with allowed values that the
+// condition and the conditions it references compare.
+func (k *conditionChecker) dependentParams(name string, visited map[string]bool) []string {
+	if visited[name] {
+		return nil
+	}
+	visited[name] = true
+	params := append([]string{}, k.params[name]...)
+	for _, ref := range k.refs[name] {
+		for _, p := range k.dependentParams(ref, visited) {
+			if !contains(params, p) {
+				params = append(params, p)
+			}
+		}
+	}
+	return params
+}
+
+// truth is the value of a condition, which may be unknown.
+type truth int8
+
+const (
+	truthUnknown truth = iota
+	truthFalse
+	truthTrue
+)
+
+func truthOf(b bool) truth {
+	if b {
+		return truthTrue
+	}
+	return truthFalse
+}
+
+// eval evaluates a condition with parameters set to assignment. visiting
+// holds the conditions being evaluated, so a cycle evaluates to unknown.
+func (k *conditionChecker) eval(v any, assignment map[string]any, visiting map[string]bool) truth {
+	switch v := v.(type) {
+	case bool:
+		return truthOf(v)
+	case Bool:
+		return truthOf(bool(v))
+	case Condition:
+		condition, ok := k.conditions[v.Name]
+		if !ok || visiting[v.Name] {
+			return truthUnknown
+		}
+		visiting[v.Name] = true
+		defer delete(visiting, v.Name)
+		return k.eval(condition, assignment, visiting)
+	case And:
+		result := truthTrue
+		for _, c := range v.Conditions {
+			switch k.eval(c, assignment, visiting) {
+			case truthFalse:
+				return truthFalse
+			case truthUnknown:
+				result = truthUnknown
+			}
+		}
+		return result
+	case Or:
+		result := truthFalse
+		for _, c := range v.Conditions {
+			switch k.eval(c, assignment, visiting) {
+			case truthTrue:
+				return truthTrue
+			case truthUnknown:
+				result = truthUnknown
+			}
+		}
+		return result
+	case Not:
+		condition := v.Condition
+		if list, ok := condition.([]any); ok {
+			if len(list) != 1 {
+				return truthUnknown
+			}
+			condition = list[0]
+		}
+		switch k.eval(condition, assignment, visiting) {
+		case truthTrue:
+			return truthFalse
+		case truthFalse:
+			return truthTrue
+		}
+		return truthUnknown
+	case Equals:
+		a, aok := operandString(v.Value1, assignment)
+		b, bok := operandString(v.Value2, assignment)
+		if aok && bok {
+			return truthOf(a == b)
+		}
+		if reflect.DeepEqual(v.Value1, v.Value2) {
+			return truthTrue
+		}
+	}
+	return truthUnknown
+}
+
+// operandString returns an Equals operand as a string if it is a literal
+// or a Ref to an assigned parameter.
+func operandString(v any, assignment map[string]any) (string, bool) {
+	switch v := v.(type) {
+	case String:
+		return string(v), true
+	case Int:
+		return scalarString(int(v))
+	case Double:
+		return scalarString(float64(v))
+	case Bool:
+		return scalarString(bool(v))
+	case Ref:
+		value, ok := assignment[v.LogicalName]
+		if !ok {
+			return "", false
+		}
+		return scalarString(value)
+	}
+	return scalarString(v)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package intrinsics_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+func TestConditions_Builder(t *testing.T) {
+	conditions := intrinsics.Conditions{}
+	isProd := conditions.Add("IsProd", intrinsics.EqualsOf(intrinsics.Ref{LogicalName: "Env"}, intrinsics.String("prod")))
+	conditions.Add("IsProdInUSEast1", intrinsics.AndOf(isProd, intrinsics.EqualsOf(intrinsics.AWS_REGION, intrinsics.String("us-east-1"))))
+	conditions.Add("IsDev", intrinsics.NotOf(isProd))
+
+	if isProd != (intrinsics.Condition{Name: "IsProd"}) {
+		t.Errorf("Add returned %v", isProd)
+	}
+
+	got := mustMarshal(t, conditions)
+	want := `{"IsDev":{"Fn::Not":[{"Condition":"IsProd"}]},"IsProd":{"Fn::Equals":[{"Ref":"Env"},"prod"]},"IsProdInUSEast1":{"Fn::And":[{"Condition":"IsProd"},{"Fn::Equals":[{"Ref":"AWS::Region"},"us-east-1"]}]}}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if issues := conditions.Check(nil); len(issues) != 0 {
+		t.Errorf("unexpected issues: %v", issues)
+	}
+}
+
+func TestConditions_Check(t *testing.T) {
+	env := intrinsics.Ref{LogicalName: "Env"}
+	isProd := intrinsics.Equals{Value1: env, Value2: "prod"}
+	isDev := intrinsics.Equals{Value1: env, Value2: "dev"}
+	allowed := map[string][]any{"Env": {"dev", "prod"}, "Size": {"small", "large"}}
+
+	tests := []struct {
+		name       string
+		conditions intrinsics.Conditions
+		want       []string
+	}{
+		{
+			name:       "valid",
+			conditions: intrinsics.Conditions{"IsProd": isProd, "IsProdOrBig": intrinsics.Or{Conditions: []any{intrinsics.Condition{Name: "IsProd"}, intrinsics.Equals{Value1: intrinsics.Ref{LogicalName: "Size"}, Value2: "large"}}}},
+		},
+		{
+			name:       "and_one_operand",
+			conditions: intrinsics.Conditions{"C": intrinsics.And{Conditions: []any{isProd}}},
+			want:       []string{"C.Fn::And: Arity: Fn::And has 1 conditions; CloudFormation allows 2 to 10"},
+		},
+		{
+			name: "or_eleven_operands",
+			conditions: intrinsics.Conditions{"C": intrinsics.Or{Conditions: []any{
+				isProd, isDev, isProd, isDev, isProd, isDev, isProd, isDev, isProd, isDev, isProd,
+			}}},
+			want: []string{
+				"C: AlwaysTrue: condition is always true for all allowed values of Env",
+				"C.Fn::Or: Arity: Fn::Or has 11 conditions; CloudFormation allows 2 to 10",
+			},
+		},
+		{
+			name: "nested_arity",
+			conditions: intrinsics.Conditions{"C": intrinsics.Or{Conditions: []any{
+				intrinsics.Equals{Value1: intrinsics.Ref{LogicalName: "Other"}, Value2: "x"},
+				intrinsics.Not{Condition: intrinsics.And{Conditions: []any{}}},
+			}}},
+			want: []string{"C.Fn::Or[1].Fn::Not.Fn::And: Arity: Fn::And has 0 conditions; CloudFormation allows 2 to 10"},
+		},
+		{
+			name:       "not_two_operands",
+			conditions: intrinsics.Conditions{"C": intrinsics.Not{Condition: []any{isProd, isDev}}},
+			want:       []string{"C.Fn::Not: Arity: Fn::Not has 2 conditions; it takes 1"},
+		},
+		{
+			name:       "long_form_not",
+			conditions: intrinsics.Conditions{"C": map[string]any{"Fn::Not": []any{map[string]any{"Condition": "A"}, map[string]any{"Condition": "B"}}}},
+			want: []string{
+				"C.Fn::Not: Arity: Fn::Not has 2 conditions; it takes 1",
+				`C.Fn::Not[0]: Undefined: condition "A" is not defined`,
+				`C.Fn::Not[1]: Undefined: condition "B" is not defined`,
+			},
+		},
+		{
+			name:       "undefined",
+			conditions: intrinsics.Conditions{"C": intrinsics.Not{Condition: intrinsics.Condition{Name: "Missing"}}},
+			want:       []string{`C.Fn::Not: Undefined: condition "Missing" is not defined`},
+		},
+		{
+			name:       "self_reference",
+			conditions: intrinsics.Conditions{"C": intrinsics.Not{Condition: intrinsics.Condition{Name: "C"}}},
+			want:       []string{"C: Cycle: circular condition reference: C -> C"},
+		},
+		{
+			name: "cycle",
+			conditions: intrinsics.Conditions{
+				"B": intrinsics.And{Conditions: []any{intrinsics.Condition{Name: "C"}, isProd}},
+				"A": intrinsics.Not{Condition: intrinsics.Condition{Name: "B"}},
+				"C": intrinsics.Or{Conditions: []any{intrinsics.Condition{Name: "A"}, isDev}},
+				"D": intrinsics.Not{Condition: intrinsics.Condition{Name: "A"}},
+			},
+			want: []string{"A: Cycle: circular condition reference: A -> B -> C -> A"},
+		},
+		{
+			name:       "always_true",
+			conditions: intrinsics.Conditions{"C": intrinsics.Or{Conditions: []any{isProd, isDev}}},
+			want:       []string{"C: AlwaysTrue: condition is always true for all allowed values of Env"},
+		},
+		{
+			name:       "always_false",
+			conditions: intrinsics.Conditions{"C": intrinsics.Equals{Value1: env, Value2: "staging"}},
+			want:       []string{"C: AlwaysFalse: condition is always false for all allowed values of Env"},
+		},
+		{
+			name: "always_false_through_reference",
+			conditions: intrinsics.Conditions{
+				"IsProd": isProd,
+				"C":      intrinsics.And{Conditions: []any{intrinsics.Condition{Name: "IsProd"}, isDev}},
+			},
+			want: []string{"C: AlwaysFalse: condition is always false for all allowed values of Env"},
+		},
+		{
+			name:       "literals",
+			conditions: intrinsics.Conditions{"C": intrinsics.Equals{Value1: "a", Value2: intrinsics.String("a")}},
+			want:       []string{"C: AlwaysTrue: condition is always true"},
+		},
+		{
+			name:       "same_operands",
+			conditions: intrinsics.Conditions{"C": intrinsics.Equals{Value1: intrinsics.AWS_REGION, Value2: intrinsics.AWS_REGION}},
+			want:       []string{"C: AlwaysTrue: condition is always true"},
+		},
+		{
+			name:       "unknown_parameter",
+			conditions: intrinsics.Conditions{"C": intrinsics.Or{Conditions: []any{isProd, intrinsics.Equals{Value1: intrinsics.Ref{LogicalName: "Other"}, Value2: "x"}}}},
+		},
+		{
+			name:       "double_literal",
+			conditions: intrinsics.Conditions{"C": intrinsics.Equals{Value1: intrinsics.Double(1.5), Value2: "1.5"}},
+			want:       []string{"C: AlwaysTrue: condition is always true"},
+		},
+		{
+			name:       "unknown_short_circuit",
+			conditions: intrinsics.Conditions{"C": intrinsics.And{Conditions: []any{intrinsics.Equals{Value1: intrinsics.AWS_REGION, Value2: "us-east-1"}, intrinsics.Equals{Value1: "a", Value2: "b"}}}},
+			want:       []string{"C: AlwaysFalse: condition is always false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range tt.conditions.Check(allowed) {
+				got = append(got, issue.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestConditions_CheckDecoded(t *testing.T) {
+	var conditions intrinsics.Conditions
+	data := `{
+		"IsProd": {"Fn::Equals": [{"Ref": "Env"}, "prod"]},
+		"IsNotProd": {"Fn::Not": [{"Condition": "IsProd"}]},
+		"Never": {"Fn::And": [{"Condition": "IsProd"}, {"Condition": "IsNotProd"}]}
+	}`
+	if err := json.Unmarshal([]byte(data), &conditions); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	issues := conditions.Check(map[string][]any{"Env": {"dev", "prod"}})
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if issues[0].Condition != "Never" || issues[0].Code != intrinsics.ConditionAlwaysFalse {
+		t.Errorf("got %v, want Never AlwaysFalse", issues[0])
+	}
+}
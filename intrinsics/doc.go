@@ -6,6 +6,71 @@
 //	GetAtt{"MyRole", "Arn"}   → {"Fn::GetAtt": ["MyRole", "Arn"]}
 //	Sub{"${AWS::Region}-x"}   → {"Fn::Sub": "${AWS::Region}-x"}
 //
+// The AWS::LanguageExtensions functions (ForEach, Length, ToJsonString,
+// FindInMapWithDefault, GetAttDynamic) and the rule functions used in the
+// Rules section (Contains, EachMemberEquals, EachMemberIn, RefAll, ValueOf,
+// ValueOfAll, with Rule and Assertion) are covered too.
+//
+// StringValue, IntValue, DoubleValue, BoolValue and ListValue[T] describe
+// what a value evaluates to. Literals (String, Int, Double, Bool, List) and
+// intrinsics implement them, and constructors such as JoinOf and SelectOf accept only values of
+// the right shape:
+//
+//	JoinOf("-", String("app"), AWS_REGION)
+//	SelectOf(0, ListOf[StringValue]{Ref{"Subnets"}})
+//
+// GetAtt is a StringValue, so list attributes go through GetAttList, and
+// a join over a list value through JoinList:
+//
+//	JoinList(",", GetAttList{"Vpc", "CidrBlockAssociations"})
+//
+// ObjectValue[T] is an object of a property type struct T generated by
+// codegen: a *T, or an intrinsic wrapped in ObjectOf[T].
+//
+// Unmarshal and UnmarshalYAML decode template fragments back into these
+// types, including short-form YAML tags, and each type implements
+// json.Unmarshaler and yaml.Unmarshaler:
+//
+//	v, err := intrinsics.UnmarshalYAML([]byte("!GetAtt MyRole.Arn")) // GetAtt{"MyRole", "Arn"}
+//
+// Each type also implements yaml.Marshaler and writes short-form tags
+// where YAML allows them. MarshalYAML and EncodeNode take a
+// ShortFormPolicy that decides which function falls back to long form
+// when one short form would be nested directly in another:
+//
+//	yaml.Marshal(Base64{Sub{"echo ${X}"}})               // Fn::Base64: !Sub echo ${X}
+//	intrinsics.MarshalYAML(v, intrinsics.ShortFormNever) // long form only
+//
+// ARN builds and parses Amazon Resource Names that use the pseudo-parameters
+// instead of hard-coded partitions, regions and accounts:
+//
+//	IAMRoleARN(Ref{"RoleName"}) // {"Fn::Sub": "arn:${AWS::Partition}:iam::${AWS::AccountId}:role/${RoleName}"}
+//
+// Resolve evaluates a value in a Context of pseudo-parameters, parameters,
+// mappings, conditions and availability zones. Values that depend on a
+// deployed stack, such as GetAtt, fail with a *ResolveError naming the node:
+//
+//	v, err := intrinsics.Resolve(Join{"-", []any{AWS_REGION, "logs"}}, ctx) // "us-east-1-logs"
+//
+// CidrSubnets and Cidr.Subnets compute Fn::Cidr offline for IPv4 and IPv6
+// blocks, with CloudFormation's limits on count and cidrBits. GetAZs.Zones
+// takes an AZProvider; BundledAZs is a static table of zones per region:
+//
+//	intrinsics.CidrSubnets("10.0.0.0/16", 2, 8) // ["10.0.0.0/24", "10.0.1.0/24"]
+//
+// Conditions holds named conditions built with the typed EqualsOf, AndOf,
+// OrOf and NotOf. Check reports And and Or without 2 to 10 conditions,
+// undefined and circular Condition references, and conditions that are
+// always true or always false given the parameters' AllowedValues:
+//
+//	issues := conditions.Check(map[string][]any{"Env": {"dev", "prod"}})
+//
+// Canonicalize rewrites equivalent forms of an expression to one form, such
+// as a Sub without variables to its string and a single-value Join to the
+// value, and Equal compares canonical forms:
+//
+//	intrinsics.Equal(GetAtt{"Role", "Arn"}, map[string]any{"Fn::GetAtt": "Role.Arn"}) // true
+//
 // Pseudo-parameters are provided as pre-defined Ref values:
 //
 //	AWS_REGION      → {"Ref": "AWS::Region"}
@@ -34,7 +34,8 @@ func Param(name string) Ref {
 }
 
 // GetAtt represents a CloudFormation Fn::GetAtt intrinsic function.
-// Use this for getting a specific attribute from a resource.
+// Use this for getting a specific attribute from a resource. GetAtt is a
+// StringValue; use GetAttList for attributes that are lists.
 //
 // Example:
 //
@@ -51,6 +52,25 @@ func (g GetAtt) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// GetAttDynamic is Fn::GetAtt with an attribute name computed by an
+// intrinsic, such as a Ref to a parameter. It requires the
+// AWS::LanguageExtensions transform.
+//
+// Example:
+//
+//	GetAttDynamic{"MyBucket", Ref{"AttrName"}} → {"Fn::GetAtt": ["MyBucket", {"Ref": "AttrName"}]}
+type GetAttDynamic struct {
+	LogicalName string
+	Attribute   any
+}
+
+// MarshalJSON serializes to CloudFormation GetAtt syntax.
+func (g GetAttDynamic) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]any{
+		"Fn::GetAtt": {g.LogicalName, g.Attribute},
+	})
+}
+
 // Sub represents a CloudFormation Fn::Sub intrinsic function.
 // Substitutes variables in a string.
 //
@@ -238,6 +258,28 @@ func (f FindInMap) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// FindInMapWithDefault is Fn::FindInMap with a DefaultValue, returned when
+// the keys are not in the mapping. It requires the AWS::LanguageExtensions
+// transform.
+//
+// Example:
+//
+//	FindInMapWithDefault{"Sizes", Ref{"Env"}, "Instance", "t3.micro"}
+//	→ {"Fn::FindInMap": ["Sizes", {"Ref": "Env"}, "Instance", {"DefaultValue": "t3.micro"}]}
+type FindInMapWithDefault struct {
+	MapName      string
+	TopKey       any
+	SecondKey    any
+	DefaultValue any
+}
+
+// MarshalJSON serializes to CloudFormation FindInMap syntax with a default.
+func (f FindInMapWithDefault) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]any{
+		"Fn::FindInMap": {f.MapName, f.TopKey, f.SecondKey, map[string]any{"DefaultValue": f.DefaultValue}},
+	})
+}
+
 // Split represents a CloudFormation Fn::Split intrinsic function.
 type Split struct {
 	Delimiter string
@@ -265,6 +307,74 @@ func (c Cidr) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// Length represents a CloudFormation Fn::Length intrinsic function, the
+// number of elements in a list. It requires the AWS::LanguageExtensions
+// transform.
+//
+// Example:
+//
+//	Length{Ref{"Subnets"}} → {"Fn::Length": {"Ref": "Subnets"}}
+type Length struct {
+	Value any
+}
+
+// MarshalJSON serializes to CloudFormation Length syntax.
+func (l Length) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"Fn::Length": l.Value,
+	})
+}
+
+// ToJsonString represents a CloudFormation Fn::ToJsonString intrinsic
+// function, which converts an object or list to a JSON string. It requires
+// the AWS::LanguageExtensions transform.
+type ToJsonString struct {
+	Value any
+}
+
+// MarshalJSON serializes to CloudFormation ToJsonString syntax.
+func (t ToJsonString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"Fn::ToJsonString": t.Value,
+	})
+}
+
+// ForEach represents a CloudFormation Fn::ForEach loop, which replicates
+// a template fragment for each element of a collection. It requires the
+// AWS::LanguageExtensions transform.
+//
+// A loop is an entry of Resources, Outputs or a property map rather than
+// a value: use Key for the entry's key. The encoding is the single-entry
+// object.
+//
+// Example:
+//
+//	ForEach{
+//	    LoopName:   "Buckets",
+//	    Identifier: "Name",
+//	    Collection: []any{"logs", "data"},
+//	    Output:     map[string]any{"${Name}Bucket": map[string]any{"Type": "AWS::S3::Bucket"}},
+//	}
+//	→ {"Fn::ForEach::Buckets": ["Name", ["logs", "data"], {"${Name}Bucket": {"Type": "AWS::S3::Bucket"}}]}
+type ForEach struct {
+	LoopName   string
+	Identifier string
+	Collection any
+	Output     map[string]any
+}
+
+// Key returns the template key of the loop, "Fn::ForEach::" + LoopName.
+func (f ForEach) Key() string {
+	return "Fn::ForEach::" + f.LoopName
+}
+
+// MarshalJSON serializes to CloudFormation ForEach syntax.
+func (f ForEach) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]any{
+		f.Key(): {f.Identifier, f.Collection, f.Output},
+	})
+}
+
 // Condition represents a CloudFormation Condition reference.
 // Used in resource Condition fields.
 type Condition struct {
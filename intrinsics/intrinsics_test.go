@@ -366,3 +366,63 @@ func TestOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAttDynamic(t *testing.T) {
+	g := intrinsics.GetAttDynamic{LogicalName: "MyBucket", Attribute: intrinsics.Ref{LogicalName: "AttrName"}}
+	got := mustMarshal(t, g)
+	want := `{"Fn::GetAtt":["MyBucket",{"Ref":"AttrName"}]}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestFindInMapWithDefault(t *testing.T) {
+	f := intrinsics.FindInMapWithDefault{
+		MapName:      "Sizes",
+		TopKey:       intrinsics.Ref{LogicalName: "Env"},
+		SecondKey:    "Instance",
+		DefaultValue: "t3.micro",
+	}
+	got := mustMarshal(t, f)
+	want := `{"Fn::FindInMap":["Sizes",{"Ref":"Env"},"Instance",{"DefaultValue":"t3.micro"}]}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestLength(t *testing.T) {
+	l := intrinsics.Length{Value: intrinsics.Ref{LogicalName: "Subnets"}}
+	got := mustMarshal(t, l)
+	want := `{"Fn::Length":{"Ref":"Subnets"}}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestToJsonString(t *testing.T) {
+	j := intrinsics.ToJsonString{Value: map[string]any{"Region": intrinsics.AWS_REGION}}
+	got := mustMarshal(t, j)
+	want := `{"Fn::ToJsonString":{"Region":{"Ref":"AWS::Region"}}}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	f := intrinsics.ForEach{
+		LoopName:   "Buckets",
+		Identifier: "Name",
+		Collection: []any{"logs", "data"},
+		Output: map[string]any{
+			"${Name}Bucket": map[string]any{"Type": "AWS::S3::Bucket"},
+		},
+	}
+	if f.Key() != "Fn::ForEach::Buckets" {
+		t.Errorf("Key() = %s", f.Key())
+	}
+	got := mustMarshal(t, f)
+	want := `{"Fn::ForEach::Buckets":["Name",["logs","data"],{"${Name}Bucket":{"Type":"AWS::S3::Bucket"}}]}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
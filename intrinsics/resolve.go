@@ -0,0 +1,667 @@
+package intrinsics
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Context holds the values Resolve needs to evaluate intrinsics: the
+// pseudo-parameters, parameter values, mappings, conditions and the
+// availability zones returned by Fn::GetAZs.
+type Context struct {
+	Region    string
+	Account   string
+	Partition string
+	StackName string
+
+	// Params maps parameter names to values, for Ref and ${Name} in Fn::Sub.
+	Params map[string]any
+	// Mappings is the Mappings section: map name, top-level key, second-level key.
+	Mappings map[string]map[string]map[string]any
+	// Conditions maps condition names to a bool or a condition expression
+	// such as Equals{...}, for Fn::If and Condition.
+	Conditions map[string]any
+	// AZs provides the availability zones for Fn::GetAZs; nil means BundledAZs.
+	AZs AZProvider
+}
+
+// ResolveError reports a value Resolve cannot compute, such as a GetAtt of
+// a resource that only exists once deployed.
+type ResolveError struct {
+	Path   string // location of the node, such as "Fn::Join[1][0]"; empty for the value itself
+	Node   any    // the unresolvable node
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *ResolveError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "value"
+	}
+	return fmt.Sprintf("intrinsics: cannot resolve %s: %s", path, e.Reason)
+}
+
+// Resolve returns the concrete value of v in the context: intrinsics at any
+// depth are evaluated, literals are returned as plain strings, numbers,
+// booleans, maps and slices, and Refs to AWS::NoValue are removed.
+// Single-key maps such as {"Ref": "X"} and {"Fn::Join": [...]} are
+// evaluated as intrinsics, including forms Decode leaves as maps, such as
+// Fn::Join over a Ref to a list parameter.
+//
+// It returns a *ResolveError for values that are not computable from the
+// context, such as GetAtt, ImportValue, or a Ref to an unknown parameter.
+//
+// Example:
+//
+//	ctx := Context{Region: "us-east-1", Partition: "aws", Account: "123456789012"}
+//	Resolve(Sub{"arn:${AWS::Partition}:s3:::logs-${AWS::Region}"}, ctx) // "arn:aws:s3:::logs-us-east-1"
+func Resolve(v any, ctx Context) (any, error) {
+	r := &resolver{ctx: ctx, conditions: make(map[string]bool), evaluating: make(map[string]bool)}
+	result, err := r.resolve(v, "")
+	if result == noValue {
+		return nil, err
+	}
+	return result, err
+}
+
+// noValue is the result of Ref AWS::NoValue, dropped from maps and lists.
+var noValue = &struct{}{}
+
+type resolver struct {
+	ctx        Context
+	conditions map[string]bool
+	evaluating map[string]bool
+}
+
+func (r *resolver) fail(path string, node any, format string, args ...any) error {
+	return &ResolveError{Path: path, Node: node, Reason: fmt.Sprintf(format, args...)}
+}
+
+func (r *resolver) resolve(v any, path string) (any, error) {
+	switch v := v.(type) {
+	case nil, string, bool, int, int64, float64:
+		return v, nil
+	case String:
+		return string(v), nil
+	case Int:
+		return int(v), nil
+	case Double:
+		return float64(v), nil
+	case Bool:
+		return bool(v), nil
+	case ARN:
+		return r.resolve(v.Build(), path)
+	case interface{ Items() []any }:
+		return r.resolve(v.Items(), path)
+	case interface{ Wrapped() any }:
+		return r.resolve(v.Wrapped(), path)
+
+	case map[string]any:
+		if len(v) == 1 {
+			for key, value := range v {
+				if key == "Ref" || key == "Condition" || strings.HasPrefix(key, "Fn::") {
+					return r.function(key, value, v, path)
+				}
+			}
+		}
+		result := make(map[string]any, len(v))
+		for key, value := range v {
+			resolved, err := r.resolve(value, joinPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			if resolved != noValue {
+				result[key] = resolved
+			}
+		}
+		return result, nil
+
+	case []any:
+		result := make([]any, 0, len(v))
+		for i, item := range v {
+			resolved, err := r.resolve(item, indexPath(path, i))
+			if err != nil {
+				return nil, err
+			}
+			if resolved != noValue {
+				result = append(result, resolved)
+			}
+		}
+		return result, nil
+
+	case Ref:
+		return r.ref(v.LogicalName, v, path)
+
+	case Sub:
+		return r.sub(v.String, nil, joinPath(path, "Fn::Sub"), v)
+
+	case SubWithMap:
+		vars := make(map[string]any, len(v.Variables))
+		for name, value := range v.Variables {
+			resolved, err := r.resolve(value, joinPath(joinPath(path, "Fn::Sub[1]"), name))
+			if err != nil {
+				return nil, err
+			}
+			vars[name] = resolved
+		}
+		return r.sub(v.String, vars, joinPath(path, "Fn::Sub[0]"), v)
+
+	case Join:
+		path = joinPath(path, "Fn::Join")
+		values, err := r.list(v.Values, indexPath(path, 1))
+		if err != nil {
+			return nil, err
+		}
+		parts := make([]string, len(values))
+		for i, value := range values {
+			s, ok := scalarString(value)
+			if !ok {
+				return nil, r.fail(indexPath(indexPath(path, 1), i), v.Values, "Fn::Join value must be a string, got %T", value)
+			}
+			parts[i] = s
+		}
+		return strings.Join(parts, v.Delimiter), nil
+
+	case Select:
+		path = joinPath(path, "Fn::Select")
+		list, err := r.list(v.List, indexPath(path, 1))
+		if err != nil {
+			return nil, err
+		}
+		if v.Index < 0 || v.Index >= len(list) {
+			return nil, r.fail(path, v, "index %d out of range for list of %d", v.Index, len(list))
+		}
+		return list[v.Index], nil
+
+	case Split:
+		path = joinPath(path, "Fn::Split")
+		source, err := r.str(v.Source, indexPath(path, 1))
+		if err != nil {
+			return nil, err
+		}
+		var result []any
+		for _, part := range strings.Split(source, v.Delimiter) {
+			result = append(result, part)
+		}
+		return result, nil
+
+	case GetAZs:
+		provider := r.ctx.AZs
+		if provider == nil {
+			provider = BundledAZs()
+		}
+		zones, err := v.Zones(provider, r.ctx.Region)
+		if err != nil {
+			return nil, r.fail(joinPath(path, "Fn::GetAZs"), v, "%s", strings.TrimPrefix(err.Error(), "intrinsics: "))
+		}
+		return toList(zones), nil
+
+	case Base64:
+		s, err := r.str(v.Value, joinPath(path, "Fn::Base64"))
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString([]byte(s)), nil
+
+	case Cidr:
+		return r.cidr(v, joinPath(path, "Fn::Cidr"))
+
+	case FindInMap:
+		return r.findInMap(v.MapName, v.TopKey, v.SecondKey, nil, false, joinPath(path, "Fn::FindInMap"), v)
+
+	case FindInMapWithDefault:
+		return r.findInMap(v.MapName, v.TopKey, v.SecondKey, v.DefaultValue, true, joinPath(path, "Fn::FindInMap"), v)
+
+	case If:
+		path = joinPath(path, "Fn::If")
+		ok, err := r.condition(v.Condition, indexPath(path, 0))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return r.resolve(v.ValueIfTrue, indexPath(path, 1))
+		}
+		return r.resolve(v.ValueIfFalse, indexPath(path, 2))
+
+	case Condition, Equals, And, Or, Not, Contains, EachMemberEquals, EachMemberIn:
+		return r.boolean(v, path)
+
+	case Length:
+		path = joinPath(path, "Fn::Length")
+		list, err := r.list(v.Value, path)
+		if err != nil {
+			return nil, err
+		}
+		return len(list), nil
+
+	case ToJsonString:
+		path = joinPath(path, "Fn::ToJsonString")
+		value, err := r.resolve(v.Value, path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, r.fail(path, v, "%v", err)
+		}
+		return string(data), nil
+
+	case Tag:
+		value, err := r.resolve(v.Value, joinPath(path, "Value"))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"Key": v.Key, "Value": value}, nil
+
+	case GetAtt:
+		return nil, r.fail(joinPath(path, "Fn::GetAtt"), v, "Fn::GetAtt %s.%s depends on a deployed resource", v.LogicalName, v.Attribute)
+	case GetAttDynamic:
+		return nil, r.fail(joinPath(path, "Fn::GetAtt"), v, "Fn::GetAtt of %s depends on a deployed resource", v.LogicalName)
+	case ImportValue:
+		return nil, r.fail(joinPath(path, "Fn::ImportValue"), v, "Fn::ImportValue depends on another stack")
+	}
+
+	// Typed collections such as a []string parameter value resolve like
+	// []any and map[string]any.
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]any, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		return r.resolve(items, path)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, r.fail(path, v, "unsupported map key type %s", rv.Type().Key())
+		}
+		m := make(map[string]any, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return r.resolve(m, path)
+	}
+	if _, ok := v.(json.Marshaler); ok {
+		return nil, r.fail(path, v, "%s cannot be resolved statically", describeNode(v))
+	}
+	return v, nil
+}
+
+// function resolves a long-form intrinsic map. Forms that Decode leaves as
+// maps, such as Fn::Join over a list Ref or Fn::FindInMap with a computed
+// map name, are decoded again once their arguments are resolved.
+func (r *resolver) function(key string, value any, node map[string]any, path string) (any, error) {
+	fn, ok, err := decodeFunction(key, value)
+	if err == nil && !ok {
+		var args any
+		if args, err = r.resolve(value, joinPath(path, key)); err != nil {
+			return nil, err
+		}
+		fn, ok, err = decodeFunction(key, args)
+	}
+	if err != nil {
+		return nil, r.fail(joinPath(path, key), node, "%s", strings.TrimPrefix(err.Error(), "intrinsics: "))
+	}
+	if !ok {
+		return nil, r.fail(joinPath(path, key), node, "%s has an unsupported form", key)
+	}
+	return r.resolve(fn, path)
+}
+
+// ref resolves a Ref to a pseudo-parameter or parameter.
+func (r *resolver) ref(name string, node any, path string) (any, error) {
+	pseudo := map[string]string{
+		"AWS::Region":    r.ctx.Region,
+		"AWS::AccountId": r.ctx.Account,
+		"AWS::Partition": r.ctx.Partition,
+		"AWS::StackName": r.ctx.StackName,
+	}
+	switch name {
+	case "AWS::NoValue":
+		return noValue, nil
+	case "AWS::URLSuffix":
+		if r.ctx.Partition == "" {
+			break
+		}
+		if r.ctx.Partition == "aws-cn" {
+			return "amazonaws.com.cn", nil
+		}
+		return "amazonaws.com", nil
+	}
+	if value, ok := pseudo[name]; ok {
+		if value == "" {
+			return nil, r.fail(joinPath(path, "Ref"), node, "%s is not set in the context", name)
+		}
+		return value, nil
+	}
+	if value, ok := r.ctx.Params[name]; ok {
+		return r.resolve(value, joinPath(path, "Ref"))
+	}
+	if strings.HasPrefix(name, "AWS::") {
+		return nil, r.fail(joinPath(path, "Ref"), node, "%s is not set in the context", name)
+	}
+	return nil, r.fail(joinPath(path, "Ref"), node, "%s is not a parameter in the context; Refs to resources depend on a deployed resource", name)
+}
+
+// sub resolves an Fn::Sub string. vars are the resolved variable map.
+func (r *resolver) sub(s string, vars map[string]any, path string, node any) (string, error) {
+	var b strings.Builder
+	last := 0
+	for _, m := range subVariable.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(unescapeSub(s[last:m[0]]))
+		name := s[m[2]:m[3]]
+
+		var value any
+		if v, ok := vars[name]; ok {
+			value = v
+		} else if resource, attr, found := strings.Cut(name, "."); found && !strings.HasPrefix(name, "AWS::") {
+			return "", r.fail(path, node, "${%s} is Fn::GetAtt %s.%s, which depends on a deployed resource", name, resource, attr)
+		} else {
+			v, err := r.ref(name, node, path)
+			if err != nil {
+				return "", err
+			}
+			value = v
+		}
+		str, ok := scalarString(value)
+		if !ok {
+			return "", r.fail(path, node, "${%s} must be a string, got %T", name, value)
+		}
+		b.WriteString(str)
+		last = m[1]
+	}
+	b.WriteString(unescapeSub(s[last:]))
+	return b.String(), nil
+}
+
+func (r *resolver) findInMap(mapName string, topKey, secondKey, def any, hasDefault bool, path string, node any) (any, error) {
+	top, err := r.str(topKey, indexPath(path, 1))
+	if err != nil {
+		return nil, err
+	}
+	second, err := r.str(secondKey, indexPath(path, 2))
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := r.ctx.Mappings[mapName][top][second]; ok {
+		return r.resolve(value, path)
+	}
+	if hasDefault {
+		return r.resolve(def, indexPath(path, 3))
+	}
+	return nil, r.fail(path, node, "mapping %s has no value for %s.%s", mapName, top, second)
+}
+
+// condition evaluates a named condition, caching the result.
+func (r *resolver) condition(name string, path string) (bool, error) {
+	if result, ok := r.conditions[name]; ok {
+		return result, nil
+	}
+	expr, ok := r.ctx.Conditions[name]
+	if !ok {
+		return false, r.fail(path, Condition{Name: name}, "condition %s is not in the context", name)
+	}
+	if r.evaluating[name] {
+		return false, r.fail(path, Condition{Name: name}, "condition %s refers to itself", name)
+	}
+	r.evaluating[name] = true
+	defer delete(r.evaluating, name)
+
+	result, err := r.boolean(expr, "Conditions."+name)
+	if err != nil {
+		return false, err
+	}
+	r.conditions[name] = result
+	return result, nil
+}
+
+// boolean evaluates a condition function or a value resolving to a bool.
+func (r *resolver) boolean(v any, path string) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case Condition:
+		return r.condition(v.Name, joinPath(path, "Condition"))
+	case Equals:
+		path = joinPath(path, "Fn::Equals")
+		a, err := r.resolve(v.Value1, indexPath(path, 0))
+		if err != nil {
+			return false, err
+		}
+		b, err := r.resolve(v.Value2, indexPath(path, 1))
+		if err != nil {
+			return false, err
+		}
+		return equalValues(a, b), nil
+	case And:
+		path = joinPath(path, "Fn::And")
+		for i, c := range v.Conditions {
+			ok, err := r.boolean(c, indexPath(path, i))
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case Or:
+		path = joinPath(path, "Fn::Or")
+		for i, c := range v.Conditions {
+			ok, err := r.boolean(c, indexPath(path, i))
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case Not:
+		ok, err := r.boolean(v.Condition, indexPath(joinPath(path, "Fn::Not"), 0))
+		return !ok, err
+	case Contains:
+		path = joinPath(path, "Fn::Contains")
+		list, err := r.list(v.List, indexPath(path, 0))
+		if err != nil {
+			return false, err
+		}
+		value, err := r.resolve(v.Value, indexPath(path, 1))
+		if err != nil {
+			return false, err
+		}
+		for _, item := range list {
+			if equalValues(item, value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case EachMemberEquals:
+		path = joinPath(path, "Fn::EachMemberEquals")
+		list, err := r.list(v.List, indexPath(path, 0))
+		if err != nil {
+			return false, err
+		}
+		value, err := r.resolve(v.Value, indexPath(path, 1))
+		if err != nil {
+			return false, err
+		}
+		for _, item := range list {
+			if !equalValues(item, value) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case EachMemberIn:
+		path = joinPath(path, "Fn::EachMemberIn")
+		check, err := r.list(v.StringsToCheck, indexPath(path, 0))
+		if err != nil {
+			return false, err
+		}
+		match, err := r.list(v.StringsToMatch, indexPath(path, 1))
+		if err != nil {
+			return false, err
+		}
+		for _, item := range check {
+			found := false
+			for _, candidate := range match {
+				found = found || equalValues(item, candidate)
+			}
+			if !found {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	value, err := r.resolve(v, path)
+	if err != nil {
+		return false, err
+	}
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case string:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b, nil
+		}
+	}
+	return false, r.fail(path, v, "condition must be a boolean, got %T", value)
+}
+
+// cidr resolves the arguments of a Cidr and computes its subnets.
+func (r *resolver) cidr(v Cidr, path string) (any, error) {
+	block, err := r.str(v.IPBlock, indexPath(path, 0))
+	if err != nil {
+		return nil, err
+	}
+	count, err := r.integer(v.Count, indexPath(path, 1))
+	if err != nil {
+		return nil, err
+	}
+	bits, err := r.integer(v.CidrBits, indexPath(path, 2))
+	if err != nil {
+		return nil, err
+	}
+	subnets, err := CidrSubnets(block, count, bits)
+	if err != nil {
+		return nil, r.fail(path, v, "%s", strings.TrimPrefix(err.Error(), "intrinsics: Fn::Cidr: "))
+	}
+	return toList(subnets), nil
+}
+
+// list resolves v to a list.
+func (r *resolver) list(v any, path string) ([]any, error) {
+	value, err := r.resolve(v, path)
+	if err != nil {
+		return nil, err
+	}
+	switch value := value.(type) {
+	case []any:
+		return value, nil
+	case string:
+		// Ref to a CommaDelimitedList parameter given as a string.
+		var list []any
+		for _, s := range strings.Split(value, ",") {
+			list = append(list, s)
+		}
+		return list, nil
+	}
+	return nil, r.fail(path, v, "expected a list, got %T", value)
+}
+
+func toList(values []string) []any {
+	list := make([]any, len(values))
+	for i, s := range values {
+		list[i] = s
+	}
+	return list
+}
+
+// str resolves v to a string.
+func (r *resolver) str(v any, path string) (string, error) {
+	value, err := r.resolve(v, path)
+	if err != nil {
+		return "", err
+	}
+	s, ok := scalarString(value)
+	if !ok {
+		return "", r.fail(path, v, "expected a string, got %T", value)
+	}
+	return s, nil
+}
+
+// integer resolves v to an int, accepting numeric strings.
+func (r *resolver) integer(v any, path string) (int, error) {
+	value, err := r.resolve(v, path)
+	if err != nil {
+		return 0, err
+	}
+	switch value := value.(type) {
+	case int:
+		return value, nil
+	case float64:
+		if value == float64(int(value)) {
+			return int(value), nil
+		}
+	case string:
+		if i, err := strconv.Atoi(value); err == nil {
+			return i, nil
+		}
+	}
+	return 0, r.fail(path, v, "expected an integer, got %v", value)
+}
+
+// scalarString formats strings, numbers and booleans as CloudFormation
+// passes them to string functions.
+func scalarString(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// equalValues compares resolved values; scalars compare as strings, as in
+// Fn::Equals.
+func equalValues(a, b any) bool {
+	as, aok := scalarString(a)
+	bs, bok := scalarString(b)
+	if aok && bok {
+		return as == bs
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// describeNode names an intrinsic by its JSON key, such as "Fn::Transform".
+func describeNode(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%T", v)
+	}
+	var m map[string]any
+	if json.Unmarshal(data, &m) == nil && len(m) == 1 {
+		return describe(m)
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
@@ -0,0 +1,174 @@
+package intrinsics_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+func testContext() intrinsics.Context {
+	return intrinsics.Context{
+		Region:    "us-east-1",
+		Account:   "123456789012",
+		Partition: "aws",
+		StackName: "app",
+		Params: map[string]any{
+			"Env":     "prod",
+			"Subnets": []string{"subnet-a", "subnet-b"},
+			"Ports":   []int{80, 443},
+			"Sizes":   map[string]string{"prod": "large"},
+			"Names":   "a,b,c",
+		},
+		Mappings: map[string]map[string]map[string]any{
+			"Sizes": {"prod": {"Instance": "m5.large"}},
+		},
+		Conditions: map[string]any{
+			"IsProd":  intrinsics.Equals{Value1: intrinsics.Ref{LogicalName: "Env"}, Value2: "prod"},
+			"NotProd": intrinsics.Not{Condition: intrinsics.Condition{Name: "IsProd"}},
+			"Always":  true,
+		},
+		AZs: intrinsics.StaticAZs{
+			"us-east-1": {"us-east-1a", "us-east-1b", "us-east-1c"},
+			"eu-west-1": {"eu-west-1a"},
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{"literal", "x", "x"},
+		{"typed_literal", intrinsics.Strings("a", "b"), []any{"a", "b"}},
+		{"pseudo", intrinsics.AWS_REGION, "us-east-1"},
+		{"url_suffix", intrinsics.AWS_URL_SUFFIX, "amazonaws.com"},
+		{"param", intrinsics.Ref{LogicalName: "Env"}, "prod"},
+		{"sub", intrinsics.Sub{String: "arn:${AWS::Partition}:s3:::${AWS::StackName}-${Env}-${!Literal}"}, "arn:aws:s3:::app-prod-${Literal}"},
+		{"sub_map", intrinsics.SubWithMap{String: "${Name}.${AWS::Region}", Variables: map[string]any{"Name": intrinsics.Ref{LogicalName: "Env"}}}, "prod.us-east-1"},
+		{"join", intrinsics.Join{Delimiter: "-", Values: []any{"a", intrinsics.Ref{LogicalName: "Env"}, 3}}, "a-prod-3"},
+		{"join_list_param", intrinsics.JoinOf(",", intrinsics.Ref{LogicalName: "Env"}), "prod"},
+		{"select", intrinsics.Select{Index: 1, List: intrinsics.GetAZs{}}, "us-east-1b"},
+		{"select_param", intrinsics.SelectOf(0, intrinsics.ListOf[intrinsics.StringValue]{Value: intrinsics.Ref{LogicalName: "Subnets"}}), "subnet-a"},
+		{"select_comma_list", intrinsics.Select{Index: 2, List: intrinsics.Ref{LogicalName: "Names"}}, "c"},
+		{"split", intrinsics.Split{Delimiter: ",", Source: "a,b"}, []any{"a", "b"}},
+		{"getazs_region", intrinsics.GetAZs{Region: "eu-west-1"}, []any{"eu-west-1a"}},
+		{"base64", intrinsics.Base64{Value: intrinsics.Sub{String: "echo ${Env}"}}, "ZWNobyBwcm9k"},
+		{"findinmap", intrinsics.FindInMap{MapName: "Sizes", TopKey: intrinsics.Ref{LogicalName: "Env"}, SecondKey: "Instance"}, "m5.large"},
+		{"findinmap_default", intrinsics.FindInMapWithDefault{MapName: "Sizes", TopKey: "dev", SecondKey: "Instance", DefaultValue: "t3.micro"}, "t3.micro"},
+		{"if", intrinsics.If{Condition: "IsProd", ValueIfTrue: 3, ValueIfFalse: 1}, 3},
+		{"if_nested_condition", intrinsics.If{Condition: "NotProd", ValueIfTrue: 3, ValueIfFalse: 1}, 1},
+		{"condition_functions", intrinsics.And{Conditions: []any{intrinsics.Condition{Name: "Always"}, intrinsics.Or{Conditions: []any{false, intrinsics.Condition{Name: "IsProd"}}}}}, true},
+		{"novalue", map[string]any{"A": intrinsics.If{Condition: "IsProd", ValueIfTrue: intrinsics.AWS_NO_VALUE, ValueIfFalse: "x"}, "B": []any{intrinsics.AWS_NO_VALUE, "y"}}, map[string]any{"B": []any{"y"}}},
+		{"length", intrinsics.Length{Value: intrinsics.GetAZs{}}, 3},
+		{"tojsonstring", intrinsics.ToJsonString{Value: map[string]any{"Env": intrinsics.Ref{LogicalName: "Env"}}}, `{"Env":"prod"}`},
+		{"arn", intrinsics.IAMRoleARN("deploy"), "arn:aws:iam::123456789012:role/deploy"},
+		{"contains", intrinsics.Contains{List: []any{"dev", "prod"}, Value: intrinsics.Ref{LogicalName: "Env"}}, true},
+		{"each_member_in", intrinsics.EachMemberIn{StringsToCheck: intrinsics.Ref{LogicalName: "Subnets"}, StringsToMatch: []any{"subnet-a"}}, false},
+		{"tag", intrinsics.Tag{Key: "Stack", Value: intrinsics.AWS_STACK_NAME}, map[string]any{"Key": "Stack", "Value": "app"}},
+		{"double", intrinsics.Double(0.5), 0.5},
+		{"ref_string_slice", intrinsics.Ref{LogicalName: "Subnets"}, []any{"subnet-a", "subnet-b"}},
+		{"select_string_slice", intrinsics.Select{Index: 1, List: intrinsics.Ref{LogicalName: "Subnets"}}, "subnet-b"},
+		{"join_string_slice", map[string]any{"Fn::Join": []any{",", intrinsics.Ref{LogicalName: "Subnets"}}}, "subnet-a,subnet-b"},
+		{"typed_slice", intrinsics.Ref{LogicalName: "Ports"}, []any{80, 443}},
+		{"typed_map", intrinsics.Ref{LogicalName: "Sizes"}, map[string]any{"prod": "large"}},
+		{"join_list", intrinsics.JoinList(",", intrinsics.ListOf[intrinsics.StringValue]{Value: intrinsics.Ref{LogicalName: "Subnets"}}), "subnet-a,subnet-b"},
+		{"long_form_ref", map[string]any{"Ref": "Env"}, "prod"},
+		{"long_form_join_list", map[string]any{"Fn::Join": []any{",", map[string]any{"Ref": "Subnets"}}}, "subnet-a,subnet-b"},
+		{"long_form_select_string_index", map[string]any{"Fn::Select": []any{"1", map[string]any{"Ref": "Subnets"}}}, "subnet-b"},
+		{"long_form_select_computed_index", map[string]any{"Fn::Select": []any{map[string]any{"Fn::Length": []any{"x"}}, []any{"a", "b"}}}, "b"},
+		{"long_form_findinmap", map[string]any{"Fn::FindInMap": []any{"Sizes", map[string]any{"Ref": "Env"}, "Instance"}}, "m5.large"},
+		{"long_form_findinmap_computed_name", map[string]any{"Fn::FindInMap": []any{map[string]any{"Fn::Join": []any{"", []any{"Siz", "es"}}}, "prod", "Instance"}}, "m5.large"},
+		{"long_form_nested", map[string]any{"Name": map[string]any{"Fn::Sub": "${Env}-x"}}, map[string]any{"Name": "prod-x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := intrinsics.Resolve(tt.value, testContext())
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve_Cidr(t *testing.T) {
+	tests := []struct {
+		cidr intrinsics.Cidr
+		want []any
+	}{
+		{intrinsics.Cidr{IPBlock: "10.0.0.0/16", Count: 3, CidrBits: 8}, []any{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"}},
+		{intrinsics.Cidr{IPBlock: "192.168.0.0/24", Count: "2", CidrBits: 6}, []any{"192.168.0.0/26", "192.168.0.64/26"}},
+		{intrinsics.Cidr{IPBlock: "2600:1f14::/56", Count: 2, CidrBits: 64}, []any{"2600:1f14::/64", "2600:1f14:0:1::/64"}},
+	}
+	for _, tt := range tests {
+		got, err := intrinsics.Resolve(intrinsics.Select{Index: 0, List: tt.cidr}, testContext())
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if got != tt.want[0] {
+			t.Errorf("Select 0 = %v, want %v", got, tt.want[0])
+		}
+		all, err := intrinsics.Resolve(tt.cidr, testContext())
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if !reflect.DeepEqual(all, tt.want) {
+			t.Errorf("got %v, want %v", all, tt.want)
+		}
+	}
+
+	if _, err := intrinsics.Resolve(intrinsics.Cidr{IPBlock: "10.0.0.0/24", Count: 5, CidrBits: 6}, testContext()); err == nil {
+		t.Error("expected error for too many subnets")
+	}
+}
+
+func TestResolve_BundledAZs(t *testing.T) {
+	got, err := intrinsics.Resolve(intrinsics.Select{Index: 0, List: intrinsics.GetAZs{}}, intrinsics.Context{Region: "us-west-2"})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got != "us-west-2a" {
+		t.Errorf("got %v, want us-west-2a", got)
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		path  string
+	}{
+		{"getatt", intrinsics.Join{Delimiter: "", Values: []any{"a", intrinsics.GetAtt{LogicalName: "R", Attribute: "Arn"}}}, "Fn::Join[1][1].Fn::GetAtt"},
+		{"resource_ref", map[string]any{"Bucket": intrinsics.Ref{LogicalName: "MyBucket"}}, "Bucket.Ref"},
+		{"sub_getatt", intrinsics.Sub{String: "${R.Arn}"}, "Fn::Sub"},
+		{"import", []any{intrinsics.ImportValue{ExportName: "x"}}, "[0].Fn::ImportValue"},
+		{"missing_mapping", intrinsics.FindInMap{MapName: "Sizes", TopKey: "dev", SecondKey: "Instance"}, "Fn::FindInMap"},
+		{"unknown_condition", intrinsics.If{Condition: "Missing", ValueIfTrue: 1, ValueIfFalse: 2}, "Fn::If[0]"},
+		{"select_range", intrinsics.Select{Index: 5, List: []any{"a"}}, "Fn::Select"},
+		{"unsupported", intrinsics.RefAll{ParameterType: "AWS::EC2::VPC::Id"}, ""},
+		{"long_form_getatt", map[string]any{"Fn::GetAtt": []any{"R", "Arn"}}, "Fn::GetAtt"},
+		{"long_form_unknown", map[string]any{"A": map[string]any{"Fn::Future": []any{"x"}}}, "A.Fn::Future"},
+		{"long_form_bad_index", map[string]any{"Fn::Select": []any{"x", []any{"a"}}}, "Fn::Select"},
+		{"long_form_bad_ref", map[string]any{"Ref": []any{"x"}}, "Ref"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := intrinsics.Resolve(tt.value, testContext())
+			var resolveErr *intrinsics.ResolveError
+			if !errors.As(err, &resolveErr) {
+				t.Fatalf("expected *ResolveError, got %v", err)
+			}
+			if resolveErr.Path != tt.path {
+				t.Errorf("Path = %q, want %q (%v)", resolveErr.Path, tt.path, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package intrinsics
+
+import (
+	"encoding/json"
+)
+
+// Rule is an entry of a template's Rules section. The assertions are
+// checked when the stack is created or updated, if RuleCondition is nil
+// or evaluates to true.
+//
+// Example:
+//
+//	Rule{
+//	    RuleCondition: Equals{Ref{"Env"}, "prod"},
+//	    Assertions: []Assertion{{
+//	        Assert:            Contains{[]any{"m5.large"}, Ref{"InstanceType"}},
+//	        AssertDescription: "prod requires m5.large",
+//	    }},
+//	}
+type Rule struct {
+	RuleCondition any
+	Assertions    []Assertion
+}
+
+// MarshalJSON serializes to CloudFormation rule syntax.
+func (r Rule) MarshalJSON() ([]byte, error) {
+	result := map[string]any{
+		"Assertions": r.Assertions,
+	}
+	if r.RuleCondition != nil {
+		result["RuleCondition"] = r.RuleCondition
+	}
+	return json.Marshal(result)
+}
+
+// Assertion is a rule assertion: a rule function that must evaluate to
+// true, with an optional message shown when it does not.
+type Assertion struct {
+	Assert            any
+	AssertDescription string
+}
+
+// MarshalJSON serializes to CloudFormation assertion syntax.
+func (a Assertion) MarshalJSON() ([]byte, error) {
+	result := map[string]any{
+		"Assert": a.Assert,
+	}
+	if a.AssertDescription != "" {
+		result["AssertDescription"] = a.AssertDescription
+	}
+	return json.Marshal(result)
+}
+
+// Contains represents the Fn::Contains rule function: true if Value is
+// one of the strings in List.
+//
+// Example:
+//
+//	Contains{[]any{"a", "b"}, Ref{"P"}} → {"Fn::Contains": [["a", "b"], {"Ref": "P"}]}
+type Contains struct {
+	List  any
+	Value any
+}
+
+// MarshalJSON serializes to CloudFormation Contains syntax.
+func (c Contains) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]any{
+		"Fn::Contains": {c.List, c.Value},
+	})
+}
+
+// EachMemberEquals represents the Fn::EachMemberEquals rule function: true
+// if every string in List equals Value.
+type EachMemberEquals struct {
+	List  any
+	Value any
+}
+
+// MarshalJSON serializes to CloudFormation EachMemberEquals syntax.
+func (e EachMemberEquals) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]any{
+		"Fn::EachMemberEquals": {e.List, e.Value},
+	})
+}
+
+// EachMemberIn represents the Fn::EachMemberIn rule function: true if
+// every string in StringsToCheck is in StringsToMatch.
+type EachMemberIn struct {
+	StringsToCheck any
+	StringsToMatch any
+}
+
+// MarshalJSON serializes to CloudFormation EachMemberIn syntax.
+func (e EachMemberIn) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]any{
+		"Fn::EachMemberIn": {e.StringsToCheck, e.StringsToMatch},
+	})
+}
+
+// RefAll represents the Fn::RefAll rule function: all values of a
+// parameter type in the account and region.
+//
+// Example:
+//
+//	RefAll{"AWS::EC2::VPC::Id"} → {"Fn::RefAll": "AWS::EC2::VPC::Id"}
+type RefAll struct {
+	ParameterType string
+}
+
+// MarshalJSON serializes to CloudFormation RefAll syntax.
+func (r RefAll) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{
+		"Fn::RefAll": r.ParameterType,
+	})
+}
+
+// ValueOf represents the Fn::ValueOf rule function: an attribute of the
+// resource a parameter refers to.
+//
+// Example:
+//
+//	ValueOf{"ElbVpc", "Tags.Department"} → {"Fn::ValueOf": ["ElbVpc", "Tags.Department"]}
+type ValueOf struct {
+	ParameterLogicalID string
+	Attribute          string
+}
+
+// MarshalJSON serializes to CloudFormation ValueOf syntax.
+func (v ValueOf) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]string{
+		"Fn::ValueOf": {v.ParameterLogicalID, v.Attribute},
+	})
+}
+
+// ValueOfAll represents the Fn::ValueOfAll rule function: an attribute of
+// all resources of a parameter type in the account and region.
+type ValueOfAll struct {
+	ParameterType string
+	Attribute     string
+}
+
+// MarshalJSON serializes to CloudFormation ValueOfAll syntax.
+func (v ValueOfAll) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]string{
+		"Fn::ValueOfAll": {v.ParameterType, v.Attribute},
+	})
+}
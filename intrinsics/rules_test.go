@@ -0,0 +1,73 @@
+package intrinsics_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+func TestRuleFunctions(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			name:  "contains",
+			value: intrinsics.Contains{List: []any{"m5.large", "m5.xlarge"}, Value: intrinsics.Ref{LogicalName: "InstanceType"}},
+			want:  `{"Fn::Contains":[["m5.large","m5.xlarge"],{"Ref":"InstanceType"}]}`,
+		},
+		{
+			name:  "each_member_equals",
+			value: intrinsics.EachMemberEquals{List: intrinsics.ValueOf{ParameterLogicalID: "Subnets", Attribute: "VpcId"}, Value: intrinsics.Ref{LogicalName: "Vpc"}},
+			want:  `{"Fn::EachMemberEquals":[{"Fn::ValueOf":["Subnets","VpcId"]},{"Ref":"Vpc"}]}`,
+		},
+		{
+			name:  "each_member_in",
+			value: intrinsics.EachMemberIn{StringsToCheck: intrinsics.ValueOfAll{ParameterType: "AWS::EC2::Subnet::Id", Attribute: "VpcId"}, StringsToMatch: intrinsics.RefAll{ParameterType: "AWS::EC2::VPC::Id"}},
+			want:  `{"Fn::EachMemberIn":[{"Fn::ValueOfAll":["AWS::EC2::Subnet::Id","VpcId"]},{"Fn::RefAll":"AWS::EC2::VPC::Id"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mustMarshal(t, tt.value)
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRule(t *testing.T) {
+	rule := intrinsics.Rule{
+		RuleCondition: intrinsics.Equals{Value1: intrinsics.Ref{LogicalName: "Env"}, Value2: "prod"},
+		Assertions: []intrinsics.Assertion{
+			{
+				Assert:            intrinsics.Contains{List: []any{"m5.large"}, Value: intrinsics.Ref{LogicalName: "InstanceType"}},
+				AssertDescription: "prod requires m5.large",
+			},
+			{Assert: intrinsics.Not{Condition: intrinsics.Equals{Value1: intrinsics.Ref{LogicalName: "Subnet"}, Value2: ""}}},
+		},
+	}
+	got := mustMarshal(t, rule)
+	want := `{"Assertions":[{"Assert":{"Fn::Contains":[["m5.large"],{"Ref":"InstanceType"}]},"AssertDescription":"prod requires m5.large"},{"Assert":{"Fn::Not":[{"Fn::Equals":[{"Ref":"Subnet"},""]}]}}],"RuleCondition":{"Fn::Equals":[{"Ref":"Env"},"prod"]}}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	var decoded intrinsics.Rule
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, rule) {
+		t.Errorf("decoded %#v, want %#v", decoded, rule)
+	}
+
+	// Rules without a condition always apply.
+	if got := mustMarshal(t, intrinsics.Rule{Assertions: []intrinsics.Assertion{{Assert: intrinsics.Condition{Name: "A"}}}}); got != `{"Assertions":[{"Assert":{"Condition":"A"}}]}` {
+		t.Errorf("got %s", got)
+	}
+}
@@ -0,0 +1,738 @@
+package intrinsics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Unmarshal decodes a JSON value, turning intrinsic function objects such as
+// {"Ref": "MyBucket"} and {"Fn::GetAtt": ["MyRole", "Arn"]} into the matching
+// types of this package at any depth. Other objects and arrays become
+// map[string]any and []any, and numbers become int or float64.
+//
+// Forms a type cannot represent, such as Fn::Join over a Ref to a list
+// parameter, and unknown Fn:: functions stay map[string]any with decoded
+// contents, so they encode back unchanged.
+func Unmarshal(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("intrinsics: %w", err)
+	}
+	return Decode(v)
+}
+
+// UnmarshalYAML is like Unmarshal for YAML, including the short-form
+// tags !Ref, !GetAtt, !Sub and the rest.
+func UnmarshalYAML(data []byte) (any, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("intrinsics: %w", err)
+	}
+	return DecodeNode(&node)
+}
+
+// DecodeNode decodes a YAML node like UnmarshalYAML.
+func DecodeNode(node *yaml.Node) (any, error) {
+	v, err := nodeValue(node)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(v)
+}
+
+// Decode converts intrinsic function objects in a value decoded from JSON
+// or YAML (maps, slices and scalars) into the types of this package.
+// Values that are already intrinsics are returned as is.
+func Decode(v any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		decoded := make(map[string]any, len(v))
+		for key, value := range v {
+			d, err := Decode(value)
+			if err != nil {
+				return nil, err
+			}
+			decoded[key] = d
+		}
+		if len(decoded) == 1 {
+			for key, value := range decoded {
+				if fn, ok, err := decodeFunction(key, value); ok || err != nil {
+					return fn, err
+				}
+			}
+		}
+		return decoded, nil
+
+	case []any:
+		decoded := make([]any, len(v))
+		for i, item := range v {
+			d, err := Decode(item)
+			if err != nil {
+				return nil, err
+			}
+			decoded[i] = d
+		}
+		return decoded, nil
+
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil && i >= math.MinInt && i <= math.MaxInt {
+			return int(i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("intrinsics: invalid number %s", v)
+		}
+		return f, nil
+	}
+	return v, nil
+}
+
+// decodeFunction returns the intrinsic for a single-key object whose value
+// is already decoded. ok is false if the object is not an intrinsic or has
+// a form the types cannot represent.
+func decodeFunction(key string, value any) (fn any, ok bool, err error) {
+	args, isList := value.([]any)
+	str, isString := value.(string)
+
+	if loop, found := strings.CutPrefix(key, "Fn::ForEach::"); found {
+		if isList && len(args) == 3 {
+			identifier, ok1 := args[0].(string)
+			output, ok2 := args[2].(map[string]any)
+			if ok1 && ok2 {
+				return ForEach{LoopName: loop, Identifier: identifier, Collection: args[1], Output: output}, true, nil
+			}
+		}
+		return nil, false, nil
+	}
+
+	switch key {
+	case "Ref":
+		if !isString {
+			return nil, false, fmt.Errorf("intrinsics: Ref must be a string, got %T", value)
+		}
+		return Ref{LogicalName: str}, true, nil
+
+	case "Condition":
+		if isString {
+			return Condition{Name: str}, true, nil
+		}
+
+	case "Fn::GetAtt":
+		if isString {
+			if name, attr, found := strings.Cut(str, "."); found {
+				return GetAtt{LogicalName: name, Attribute: attr}, true, nil
+			}
+			return nil, false, fmt.Errorf("intrinsics: Fn::GetAtt %q must have the form Resource.Attribute", str)
+		}
+		if isList && len(args) == 2 {
+			if name, ok := args[0].(string); ok {
+				if attr, ok := args[1].(string); ok {
+					return GetAtt{LogicalName: name, Attribute: attr}, true, nil
+				}
+				return GetAttDynamic{LogicalName: name, Attribute: args[1]}, true, nil
+			}
+		}
+
+	case "Fn::Sub":
+		if isString {
+			return Sub{String: str}, true, nil
+		}
+		if isList && len(args) == 1 {
+			if s, ok := args[0].(string); ok {
+				return Sub{String: s}, true, nil
+			}
+		}
+		if isList && len(args) == 2 {
+			s, ok1 := args[0].(string)
+			vars, ok2 := args[1].(map[string]any)
+			if ok1 && ok2 {
+				return SubWithMap{String: s, Variables: vars}, true, nil
+			}
+		}
+
+	case "Fn::Join":
+		if isList && len(args) == 2 {
+			delim, ok1 := args[0].(string)
+			values, ok2 := args[1].([]any)
+			if ok1 && ok2 {
+				return Join{Delimiter: delim, Values: values}, true, nil
+			}
+		}
+
+	case "Fn::Select":
+		if isList && len(args) == 2 {
+			if index, ok := toInt(args[0]); ok {
+				return Select{Index: index, List: args[1]}, true, nil
+			}
+		}
+
+	case "Fn::GetAZs":
+		if isString {
+			return GetAZs{Region: str}, true, nil
+		}
+
+	case "Fn::If":
+		if isList && len(args) == 3 {
+			if cond, ok := args[0].(string); ok {
+				return If{Condition: cond, ValueIfTrue: args[1], ValueIfFalse: args[2]}, true, nil
+			}
+		}
+
+	case "Fn::Equals":
+		if isList && len(args) == 2 {
+			return Equals{Value1: args[0], Value2: args[1]}, true, nil
+		}
+
+	case "Fn::And":
+		if isList {
+			return And{Conditions: args}, true, nil
+		}
+
+	case "Fn::Or":
+		if isList {
+			return Or{Conditions: args}, true, nil
+		}
+
+	case "Fn::Not":
+		if isList && len(args) == 1 {
+			return Not{Condition: args[0]}, true, nil
+		}
+
+	case "Fn::Base64":
+		return Base64{Value: value}, true, nil
+
+	case "Fn::ImportValue":
+		return ImportValue{ExportName: value}, true, nil
+
+	case "Fn::FindInMap":
+		if isList && len(args) == 3 {
+			if name, ok := args[0].(string); ok {
+				return FindInMap{MapName: name, TopKey: args[1], SecondKey: args[2]}, true, nil
+			}
+		}
+		if isList && len(args) == 4 {
+			name, ok1 := args[0].(string)
+			def, ok2 := args[3].(map[string]any)
+			if value, ok3 := def["DefaultValue"]; ok1 && ok2 && ok3 && len(def) == 1 {
+				return FindInMapWithDefault{MapName: name, TopKey: args[1], SecondKey: args[2], DefaultValue: value}, true, nil
+			}
+		}
+
+	case "Fn::Split":
+		if isList && len(args) == 2 {
+			if delim, ok := args[0].(string); ok {
+				return Split{Delimiter: delim, Source: args[1]}, true, nil
+			}
+		}
+
+	case "Fn::Cidr":
+		if isList && len(args) == 3 {
+			return Cidr{IPBlock: args[0], Count: args[1], CidrBits: args[2]}, true, nil
+		}
+
+	case "Fn::Length":
+		return Length{Value: value}, true, nil
+
+	case "Fn::ToJsonString":
+		return ToJsonString{Value: value}, true, nil
+
+	case "Fn::Contains":
+		if isList && len(args) == 2 {
+			return Contains{List: args[0], Value: args[1]}, true, nil
+		}
+
+	case "Fn::EachMemberEquals":
+		if isList && len(args) == 2 {
+			return EachMemberEquals{List: args[0], Value: args[1]}, true, nil
+		}
+
+	case "Fn::EachMemberIn":
+		if isList && len(args) == 2 {
+			return EachMemberIn{StringsToCheck: args[0], StringsToMatch: args[1]}, true, nil
+		}
+
+	case "Fn::RefAll":
+		if isString {
+			return RefAll{ParameterType: str}, true, nil
+		}
+
+	case "Fn::ValueOf":
+		if isList && len(args) == 2 {
+			param, ok1 := args[0].(string)
+			attr, ok2 := args[1].(string)
+			if ok1 && ok2 {
+				return ValueOf{ParameterLogicalID: param, Attribute: attr}, true, nil
+			}
+		}
+
+	case "Fn::ValueOfAll":
+		if isList && len(args) == 2 {
+			paramType, ok1 := args[0].(string)
+			attr, ok2 := args[1].(string)
+			if ok1 && ok2 {
+				return ValueOfAll{ParameterType: paramType, Attribute: attr}, true, nil
+			}
+		}
+
+	case "Fn::Transform":
+		if m, ok := value.(map[string]any); ok {
+			name, ok := m["Name"].(string)
+			params, _ := m["Parameters"].(map[string]any)
+			if ok && len(m) <= 2 && (params != nil || m["Parameters"] == nil) {
+				return Transform{Name: name, Parameters: params}, true, nil
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+// toInt converts a Select index given as a number or numeric string.
+func toInt(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v), true
+		}
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// nodeValue converts a YAML node to maps, slices and scalars, rewriting
+// short-form tags to their long form: !Ref X becomes {"Ref": "X"} and
+// !Sub X becomes {"Fn::Sub": "X"}.
+func nodeValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return nodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return nodeValue(node.Alias)
+	}
+
+	if tag := node.Tag; strings.HasPrefix(tag, "!") && !strings.HasPrefix(tag, "!!") {
+		name := strings.TrimPrefix(tag, "!")
+		if name != "Ref" && name != "Condition" {
+			name = "Fn::" + name
+		}
+		var value any
+		if node.Kind == yaml.ScalarNode {
+			value = node.Value
+		} else {
+			untagged := *node
+			untagged.Tag = ""
+			v, err := nodeValue(&untagged)
+			if err != nil {
+				return nil, err
+			}
+			value = v
+		}
+		return map[string]any{name: value}, nil
+	}
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		result := make([]any, 0, len(node.Content))
+		for _, child := range node.Content {
+			v, err := nodeValue(child)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, v)
+		}
+		return result, nil
+
+	case yaml.MappingNode:
+		result := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v, err := nodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			result[node.Content[i].Value] = v
+		}
+		return result, nil
+
+	default:
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return nil, fmt.Errorf("intrinsics: line %d: %w", node.Line, err)
+		}
+		return value, nil
+	}
+}
+
+// unmarshalJSON decodes data into target, which must receive an intrinsic
+// of type T.
+func unmarshalJSON[T any](data []byte, target *T) error {
+	v, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	return assign(v, target)
+}
+
+// unmarshalYAML decodes node into target like unmarshalJSON.
+func unmarshalYAML[T any](node *yaml.Node, target *T) error {
+	v, err := DecodeNode(node)
+	if err != nil {
+		return err
+	}
+	return assign(v, target)
+}
+
+func assign[T any](v any, target *T) error {
+	t, ok := v.(T)
+	if !ok {
+		return fmt.Errorf("intrinsics: cannot decode %s into %T", describe(v), *target)
+	}
+	*target = t
+	return nil
+}
+
+// describe names a decoded value for error messages.
+func describe(v any) string {
+	if m, ok := v.(map[string]any); ok && len(m) == 1 {
+		for key := range m {
+			return key
+		}
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// UnmarshalJSON decodes {"Ref": ...}.
+func (r *Ref) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, r) }
+
+// UnmarshalYAML decodes !Ref or {Ref: ...}.
+func (r *Ref) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, r) }
+
+// UnmarshalJSON decodes {"Fn::GetAtt": ...} in list or dotted form.
+func (g *GetAtt) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, g) }
+
+// UnmarshalYAML decodes !GetAtt or {Fn::GetAtt: ...}.
+func (g *GetAtt) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, g) }
+
+// UnmarshalJSON decodes {"Fn::Sub": "..."}.
+func (s *Sub) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, s) }
+
+// UnmarshalYAML decodes !Sub or {Fn::Sub: ...}.
+func (s *Sub) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, s) }
+
+// UnmarshalJSON decodes {"Fn::Sub": ["...", {...}]}.
+func (s *SubWithMap) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, s) }
+
+// UnmarshalYAML decodes !Sub or {Fn::Sub: ...} with a variable map.
+func (s *SubWithMap) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, s) }
+
+// UnmarshalJSON decodes {"Fn::Join": ...}.
+func (j *Join) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, j) }
+
+// UnmarshalYAML decodes !Join or {Fn::Join: ...}.
+func (j *Join) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, j) }
+
+// UnmarshalJSON decodes {"Fn::Select": ...}.
+func (s *Select) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, s) }
+
+// UnmarshalYAML decodes !Select or {Fn::Select: ...}.
+func (s *Select) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, s) }
+
+// UnmarshalJSON decodes {"Fn::GetAZs": ...}.
+func (g *GetAZs) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, g) }
+
+// UnmarshalYAML decodes !GetAZs or {Fn::GetAZs: ...}.
+func (g *GetAZs) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, g) }
+
+// UnmarshalJSON decodes {"Fn::If": ...}.
+func (i *If) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, i) }
+
+// UnmarshalYAML decodes !If or {Fn::If: ...}.
+func (i *If) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, i) }
+
+// UnmarshalJSON decodes {"Fn::Equals": ...}.
+func (e *Equals) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, e) }
+
+// UnmarshalYAML decodes !Equals or {Fn::Equals: ...}.
+func (e *Equals) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, e) }
+
+// UnmarshalJSON decodes {"Fn::And": ...}.
+func (a *And) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, a) }
+
+// UnmarshalYAML decodes !And or {Fn::And: ...}.
+func (a *And) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, a) }
+
+// UnmarshalJSON decodes {"Fn::Or": ...}.
+func (o *Or) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, o) }
+
+// UnmarshalYAML decodes !Or or {Fn::Or: ...}.
+func (o *Or) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, o) }
+
+// UnmarshalJSON decodes {"Fn::Not": ...}.
+func (n *Not) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, n) }
+
+// UnmarshalYAML decodes !Not or {Fn::Not: ...}.
+func (n *Not) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, n) }
+
+// UnmarshalJSON decodes {"Fn::Base64": ...}.
+func (b *Base64) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, b) }
+
+// UnmarshalYAML decodes !Base64 or {Fn::Base64: ...}.
+func (b *Base64) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, b) }
+
+// UnmarshalJSON decodes {"Fn::ImportValue": ...}.
+func (i *ImportValue) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, i) }
+
+// UnmarshalYAML decodes !ImportValue or {Fn::ImportValue: ...}.
+func (i *ImportValue) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, i) }
+
+// UnmarshalJSON decodes {"Fn::FindInMap": ...}.
+func (f *FindInMap) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, f) }
+
+// UnmarshalYAML decodes !FindInMap or {Fn::FindInMap: ...}.
+func (f *FindInMap) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, f) }
+
+// UnmarshalJSON decodes {"Fn::Split": ...}.
+func (s *Split) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, s) }
+
+// UnmarshalYAML decodes !Split or {Fn::Split: ...}.
+func (s *Split) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, s) }
+
+// UnmarshalJSON decodes {"Fn::Cidr": ...}.
+func (c *Cidr) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, c) }
+
+// UnmarshalYAML decodes !Cidr or {Fn::Cidr: ...}.
+func (c *Cidr) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, c) }
+
+// UnmarshalJSON decodes {"Condition": ...}.
+func (c *Condition) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, c) }
+
+// UnmarshalYAML decodes !Condition or {Condition: ...}.
+func (c *Condition) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, c) }
+
+// UnmarshalJSON decodes {"Fn::Transform": {"Name": ..., "Parameters": ...}}.
+func (t *Transform) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, t) }
+
+// UnmarshalYAML decodes !Transform or {Fn::Transform: ...}.
+func (t *Transform) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, t) }
+
+// UnmarshalJSON decodes {"Fn::GetAtt": [..., {...}]}.
+func (g *GetAttDynamic) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, g) }
+
+// UnmarshalYAML decodes !GetAtt or {Fn::GetAtt: ...} with a computed attribute.
+func (g *GetAttDynamic) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, g) }
+
+// UnmarshalJSON decodes {"Fn::FindInMap": [..., {"DefaultValue": ...}]}.
+func (f *FindInMapWithDefault) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, f) }
+
+// UnmarshalYAML decodes !FindInMap or {Fn::FindInMap: ...} with a DefaultValue.
+func (f *FindInMapWithDefault) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, f) }
+
+// UnmarshalJSON decodes {"Fn::Length": ...}.
+func (l *Length) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, l) }
+
+// UnmarshalYAML decodes !Length or {Fn::Length: ...}.
+func (l *Length) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, l) }
+
+// UnmarshalJSON decodes {"Fn::ToJsonString": ...}.
+func (t *ToJsonString) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, t) }
+
+// UnmarshalYAML decodes !ToJsonString or {Fn::ToJsonString: ...}.
+func (t *ToJsonString) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, t) }
+
+// UnmarshalJSON decodes {"Fn::ForEach::Name": [...]}.
+func (f *ForEach) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, f) }
+
+// UnmarshalYAML decodes {Fn::ForEach::Name: [...]}.
+func (f *ForEach) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, f) }
+
+// UnmarshalJSON decodes {"Fn::Contains": ...}.
+func (c *Contains) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, c) }
+
+// UnmarshalYAML decodes !Contains or {Fn::Contains: ...}.
+func (c *Contains) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, c) }
+
+// UnmarshalJSON decodes {"Fn::EachMemberEquals": ...}.
+func (e *EachMemberEquals) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, e) }
+
+// UnmarshalYAML decodes !EachMemberEquals or {Fn::EachMemberEquals: ...}.
+func (e *EachMemberEquals) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, e) }
+
+// UnmarshalJSON decodes {"Fn::EachMemberIn": ...}.
+func (e *EachMemberIn) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, e) }
+
+// UnmarshalYAML decodes !EachMemberIn or {Fn::EachMemberIn: ...}.
+func (e *EachMemberIn) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, e) }
+
+// UnmarshalJSON decodes {"Fn::RefAll": ...}.
+func (r *RefAll) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, r) }
+
+// UnmarshalYAML decodes !RefAll or {Fn::RefAll: ...}.
+func (r *RefAll) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, r) }
+
+// UnmarshalJSON decodes {"Fn::ValueOf": ...}.
+func (v *ValueOf) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, v) }
+
+// UnmarshalYAML decodes !ValueOf or {Fn::ValueOf: ...}.
+func (v *ValueOf) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, v) }
+
+// UnmarshalJSON decodes {"Fn::ValueOfAll": ...}.
+func (v *ValueOfAll) UnmarshalJSON(data []byte) error { return unmarshalJSON(data, v) }
+
+// UnmarshalYAML decodes !ValueOfAll or {Fn::ValueOfAll: ...}.
+func (v *ValueOfAll) UnmarshalYAML(node *yaml.Node) error { return unmarshalYAML(node, v) }
+
+// UnmarshalJSON decodes {"Key": ..., "Value": ...}.
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	v, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	return t.fromValue(v)
+}
+
+// UnmarshalYAML decodes a tag mapping; the value may use short-form tags.
+func (t *Tag) UnmarshalYAML(node *yaml.Node) error {
+	v, err := DecodeNode(node)
+	if err != nil {
+		return err
+	}
+	return t.fromValue(v)
+}
+
+func (t *Tag) fromValue(v any) error {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("intrinsics: tag must be an object, got %s", describe(v))
+	}
+	key, ok := m["Key"].(string)
+	if !ok {
+		return fmt.Errorf("intrinsics: tag Key must be a string, got %T", m["Key"])
+	}
+	*t = Tag{Key: key, Value: m["Value"]}
+	return nil
+}
+
+// UnmarshalJSON decodes an output with Value, Description, Export and Condition.
+func (o *Output) UnmarshalJSON(data []byte) error {
+	v, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	return o.fromValue(v)
+}
+
+// UnmarshalYAML decodes an output; values may use short-form tags.
+func (o *Output) UnmarshalYAML(node *yaml.Node) error {
+	v, err := DecodeNode(node)
+	if err != nil {
+		return err
+	}
+	return o.fromValue(v)
+}
+
+func (o *Output) fromValue(v any) error {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("intrinsics: output must be an object, got %s", describe(v))
+	}
+	out := Output{Value: m["Value"]}
+	if d, ok := m["Description"].(string); ok {
+		out.Description = d
+	}
+	if c, ok := m["Condition"].(string); ok {
+		out.Condition = c
+	}
+	if export, ok := m["Export"].(map[string]any); ok {
+		out.ExportName = export["Name"]
+	}
+	*o = out
+	return nil
+}
+
+// UnmarshalJSON decodes a rule with RuleCondition and Assertions.
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	v, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	return r.fromValue(v)
+}
+
+// UnmarshalYAML decodes a rule; conditions and assertions may use short-form tags.
+func (r *Rule) UnmarshalYAML(node *yaml.Node) error {
+	v, err := DecodeNode(node)
+	if err != nil {
+		return err
+	}
+	return r.fromValue(v)
+}
+
+func (r *Rule) fromValue(v any) error {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("intrinsics: rule must be an object, got %s", describe(v))
+	}
+	rule := Rule{RuleCondition: m["RuleCondition"]}
+	assertions, ok := m["Assertions"].([]any)
+	if !ok {
+		return fmt.Errorf("intrinsics: rule Assertions must be a list, got %T", m["Assertions"])
+	}
+	for _, item := range assertions {
+		var a Assertion
+		if err := a.fromValue(item); err != nil {
+			return err
+		}
+		rule.Assertions = append(rule.Assertions, a)
+	}
+	*r = rule
+	return nil
+}
+
+// UnmarshalJSON decodes an assertion with Assert and AssertDescription.
+func (a *Assertion) UnmarshalJSON(data []byte) error {
+	v, err := Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	return a.fromValue(v)
+}
+
+// UnmarshalYAML decodes an assertion; Assert may use short-form tags.
+func (a *Assertion) UnmarshalYAML(node *yaml.Node) error {
+	v, err := DecodeNode(node)
+	if err != nil {
+		return err
+	}
+	return a.fromValue(v)
+}
+
+func (a *Assertion) fromValue(v any) error {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("intrinsics: assertion must be an object, got %s", describe(v))
+	}
+	assert, ok := m["Assert"]
+	if !ok {
+		return fmt.Errorf("intrinsics: assertion has no Assert")
+	}
+	out := Assertion{Assert: assert}
+	if d, ok := m["AssertDescription"].(string); ok {
+		out.AssertDescription = d
+	}
+	*a = out
+	return nil
+}
@@ -0,0 +1,179 @@
+package intrinsics_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want any
+	}{
+		{"ref", `{"Ref": "MyBucket"}`, intrinsics.Ref{LogicalName: "MyBucket"}},
+		{"getatt_list", `{"Fn::GetAtt": ["MyRole", "Arn"]}`, intrinsics.GetAtt{LogicalName: "MyRole", Attribute: "Arn"}},
+		{"getatt_dotted", `{"Fn::GetAtt": "Db.Endpoint.Address"}`, intrinsics.GetAtt{LogicalName: "Db", Attribute: "Endpoint.Address"}},
+		{"sub", `{"Fn::Sub": "${AWS::Region}-x"}`, intrinsics.Sub{String: "${AWS::Region}-x"}},
+		{"sub_map", `{"Fn::Sub": ["${B}", {"B": {"Ref": "Bucket"}}]}`, intrinsics.SubWithMap{String: "${B}", Variables: map[string]any{"B": intrinsics.Ref{LogicalName: "Bucket"}}}},
+		{"join", `{"Fn::Join": [",", ["a", {"Ref": "B"}]]}`, intrinsics.Join{Delimiter: ",", Values: []any{"a", intrinsics.Ref{LogicalName: "B"}}}},
+		{"select", `{"Fn::Select": [1, {"Fn::GetAZs": ""}]}`, intrinsics.Select{Index: 1, List: intrinsics.GetAZs{}}},
+		{"if", `{"Fn::If": ["Prod", 3, {"Ref": "AWS::NoValue"}]}`, intrinsics.If{Condition: "Prod", ValueIfTrue: 3, ValueIfFalse: intrinsics.AWS_NO_VALUE}},
+		{"equals", `{"Fn::Equals": [{"Ref": "Env"}, "prod"]}`, intrinsics.Equals{Value1: intrinsics.Ref{LogicalName: "Env"}, Value2: "prod"}},
+		{"and", `{"Fn::And": [{"Condition": "A"}, {"Condition": "B"}]}`, intrinsics.And{Conditions: []any{intrinsics.Condition{Name: "A"}, intrinsics.Condition{Name: "B"}}}},
+		{"or", `{"Fn::Or": [{"Condition": "A"}]}`, intrinsics.Or{Conditions: []any{intrinsics.Condition{Name: "A"}}}},
+		{"not", `{"Fn::Not": [{"Condition": "A"}]}`, intrinsics.Not{Condition: intrinsics.Condition{Name: "A"}}},
+		{"base64", `{"Fn::Base64": {"Fn::Sub": "x"}}`, intrinsics.Base64{Value: intrinsics.Sub{String: "x"}}},
+		{"import", `{"Fn::ImportValue": "shared-vpc"}`, intrinsics.ImportValue{ExportName: "shared-vpc"}},
+		{"findinmap", `{"Fn::FindInMap": ["M", {"Ref": "AWS::Region"}, "Ami"]}`, intrinsics.FindInMap{MapName: "M", TopKey: intrinsics.AWS_REGION, SecondKey: "Ami"}},
+		{"split", `{"Fn::Split": [",", "a,b"]}`, intrinsics.Split{Delimiter: ",", Source: "a,b"}},
+		{"cidr", `{"Fn::Cidr": ["10.0.0.0/16", 6, 5]}`, intrinsics.Cidr{IPBlock: "10.0.0.0/16", Count: 6, CidrBits: 5}},
+		{"transform", `{"Fn::Transform": {"Name": "AWS::Include", "Parameters": {"Location": "s3://b/k"}}}`, intrinsics.Transform{Name: "AWS::Include", Parameters: map[string]any{"Location": "s3://b/k"}}},
+		{"getatt_dynamic", `{"Fn::GetAtt": ["B", {"Ref": "Attr"}]}`, intrinsics.GetAttDynamic{LogicalName: "B", Attribute: intrinsics.Ref{LogicalName: "Attr"}}},
+		{"findinmap_default", `{"Fn::FindInMap": ["M", "a", "b", {"DefaultValue": 1}]}`, intrinsics.FindInMapWithDefault{MapName: "M", TopKey: "a", SecondKey: "b", DefaultValue: 1}},
+		{"length", `{"Fn::Length": {"Ref": "L"}}`, intrinsics.Length{Value: intrinsics.Ref{LogicalName: "L"}}},
+		{"tojsonstring", `{"Fn::ToJsonString": {"A": 1}}`, intrinsics.ToJsonString{Value: map[string]any{"A": 1}}},
+		{"foreach", `{"Fn::ForEach::Topics": ["T", ["a"], {"${T}": {"Type": "AWS::SNS::Topic"}}]}`, intrinsics.ForEach{LoopName: "Topics", Identifier: "T", Collection: []any{"a"}, Output: map[string]any{"${T}": map[string]any{"Type": "AWS::SNS::Topic"}}}},
+		{"contains", `{"Fn::Contains": [["a"], {"Ref": "P"}]}`, intrinsics.Contains{List: []any{"a"}, Value: intrinsics.Ref{LogicalName: "P"}}},
+		{"each_member_equals", `{"Fn::EachMemberEquals": [["a"], "a"]}`, intrinsics.EachMemberEquals{List: []any{"a"}, Value: "a"}},
+		{"each_member_in", `{"Fn::EachMemberIn": [["a"], ["a", "b"]]}`, intrinsics.EachMemberIn{StringsToCheck: []any{"a"}, StringsToMatch: []any{"a", "b"}}},
+		{"refall", `{"Fn::RefAll": "AWS::EC2::VPC::Id"}`, intrinsics.RefAll{ParameterType: "AWS::EC2::VPC::Id"}},
+		{"valueof", `{"Fn::ValueOf": ["Vpc", "Tags.Env"]}`, intrinsics.ValueOf{ParameterLogicalID: "Vpc", Attribute: "Tags.Env"}},
+		{"valueofall", `{"Fn::ValueOfAll": ["AWS::EC2::VPC::Id", "Tags.Env"]}`, intrinsics.ValueOfAll{ParameterType: "AWS::EC2::VPC::Id", Attribute: "Tags.Env"}},
+		{"nested_object", `{"A": [{"Ref": "X"}, 1.5, true]}`, map[string]any{"A": []any{intrinsics.Ref{LogicalName: "X"}, 1.5, true}}},
+		{"join_over_list_ref", `{"Fn::Join": [",", {"Ref": "Subnets"}]}`, map[string]any{"Fn::Join": []any{",", intrinsics.Ref{LogicalName: "Subnets"}}}},
+		{"select_string_index", `{"Fn::Select": ["1", ["a", "b"]]}`, intrinsics.Select{Index: 1, List: []any{"a", "b"}}},
+		{"unknown_function", `{"Fn::Future": ["x"]}`, map[string]any{"Fn::Future": []any{"x"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := intrinsics.Unmarshal([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshal_RoundTrip(t *testing.T) {
+	inputs := []string{
+		`{"Fn::Join":["",["arn:",{"Ref":"AWS::Partition"},":s3:::",{"Ref":"B"}]]}`,
+		`{"Fn::If":["C",{"Fn::GetAtt":["R","Arn"]},{"Ref":"AWS::NoValue"}]}`,
+		`{"Fn::Join":[",",{"Ref":"Subnets"}]}`,
+		`{"Fn::Select":[0,{"Fn::Split":[",",{"Fn::ImportValue":"x"}]}]}`,
+	}
+	for _, input := range inputs {
+		v, err := intrinsics.Unmarshal([]byte(input))
+		if err != nil {
+			t.Fatalf("Unmarshal(%s) failed: %v", input, err)
+		}
+		if got := mustMarshal(t, v); got != input {
+			t.Errorf("got %s, want %s", got, input)
+		}
+	}
+}
+
+func TestUnmarshal_Errors(t *testing.T) {
+	for _, input := range []string{`{"Ref": 1}`, `{"Fn::GetAtt": "NoDot"}`, `{`} {
+		if _, err := intrinsics.Unmarshal([]byte(input)); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want any
+	}{
+		{"ref", `!Ref MyBucket`, intrinsics.Ref{LogicalName: "MyBucket"}},
+		{"getatt", `!GetAtt MyRole.Arn`, intrinsics.GetAtt{LogicalName: "MyRole", Attribute: "Arn"}},
+		{"sub", `!Sub "${AWS::StackName}-x"`, intrinsics.Sub{String: "${AWS::StackName}-x"}},
+		{"condition", `!Condition IsProd`, intrinsics.Condition{Name: "IsProd"}},
+		{"getazs", `!GetAZs ''`, intrinsics.GetAZs{}},
+		{"nested", `!Select ["0", !GetAZs {Ref: AWS::Region}]`, intrinsics.Select{Index: 0, List: map[string]any{"Fn::GetAZs": intrinsics.AWS_REGION}}},
+		{"long_form", "Fn::Join:\n  - '-'\n  - [a, !Ref B]", intrinsics.Join{Delimiter: "-", Values: []any{"a", intrinsics.Ref{LogicalName: "B"}}}},
+		{"base64_mapping", "!Base64\n  Fn::Sub: echo ${X}", intrinsics.Base64{Value: intrinsics.Sub{String: "echo ${X}"}}},
+		{"if", `!If [Prod, !Ref Big, small]`, intrinsics.If{Condition: "Prod", ValueIfTrue: intrinsics.Ref{LogicalName: "Big"}, ValueIfFalse: "small"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := intrinsics.UnmarshalYAML([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("UnmarshalYAML failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypes_UnmarshalJSON(t *testing.T) {
+	var ref intrinsics.Ref
+	if err := json.Unmarshal([]byte(`{"Ref":"B"}`), &ref); err != nil || ref.LogicalName != "B" {
+		t.Errorf("got %+v, %v", ref, err)
+	}
+
+	var join intrinsics.Join
+	if err := json.Unmarshal([]byte(`{"Ref":"B"}`), &join); err == nil {
+		t.Error("expected error decoding Ref into Join")
+	}
+
+	// Intrinsics nested in typed fields are decoded too.
+	var props struct {
+		Name   intrinsics.Sub
+		Tags   []intrinsics.Tag
+		Output intrinsics.Output
+	}
+	data := `{"Name":{"Fn::Sub":"${A}"},"Tags":[{"Key":"Env","Value":{"Ref":"Env"}}],"Output":{"Value":{"Fn::GetAtt":["R","Arn"]},"Export":{"Name":"x"}}}`
+	if err := json.Unmarshal([]byte(data), &props); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if props.Name.String != "${A}" {
+		t.Errorf("Name = %+v", props.Name)
+	}
+	if props.Tags[0].Value != (intrinsics.Ref{LogicalName: "Env"}) {
+		t.Errorf("Tags = %+v", props.Tags)
+	}
+	if props.Output.Value != (intrinsics.GetAtt{LogicalName: "R", Attribute: "Arn"}) || props.Output.ExportName != "x" {
+		t.Errorf("Output = %+v", props.Output)
+	}
+	if got := mustMarshal(t, props.Output); got != `{"Export":{"Name":"x"},"Value":{"Fn::GetAtt":["R","Arn"]}}` {
+		t.Errorf("re-encoded output = %s", got)
+	}
+}
+
+func TestTypes_UnmarshalYAML(t *testing.T) {
+	var doc struct {
+		Bucket intrinsics.Ref    `yaml:"Bucket"`
+		Arn    intrinsics.GetAtt `yaml:"Arn"`
+		Tag    intrinsics.Tag    `yaml:"Tag"`
+	}
+	src := "Bucket: !Ref MyBucket\nArn: !GetAtt MyBucket.Arn\nTag:\n  Key: Name\n  Value: !Sub '${AWS::StackName}'\n"
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if doc.Bucket.LogicalName != "MyBucket" || doc.Arn.Attribute != "Arn" {
+		t.Errorf("got %+v", doc)
+	}
+	if doc.Tag.Value != (intrinsics.Sub{String: "${AWS::StackName}"}) {
+		t.Errorf("Tag = %+v", doc.Tag)
+	}
+
+	var sub intrinsics.Sub
+	if err := yaml.Unmarshal([]byte(`!Ref X`), &sub); err == nil {
+		t.Error("expected error decoding !Ref into Sub")
+	}
+}
@@ -0,0 +1,285 @@
+package intrinsics
+
+import (
+	"encoding/json"
+)
+
+// StringValue is a template value that evaluates to a string: a String
+// literal or an intrinsic such as Ref, GetAtt, Sub or Join.
+//
+// Generated code can use StringValue and the other typed value interfaces
+// instead of any, so that passing a list where a string is expected, such
+// as a Join over a list attribute, fails to compile.
+type StringValue interface {
+	stringValue()
+}
+
+// IntValue is a template value that evaluates to an integer: an Int
+// literal or an intrinsic such as Ref, FindInMap or Length.
+type IntValue interface {
+	intValue()
+}
+
+// DoubleValue is a template value that evaluates to a number that may have
+// a fractional part: a Double or Int literal or an intrinsic such as Ref,
+// FindInMap or Length.
+type DoubleValue interface {
+	doubleValue()
+}
+
+// BoolValue is a template value that evaluates to a boolean: a Bool literal,
+// a condition function such as Equals, or an intrinsic such as Ref or If.
+type BoolValue interface {
+	boolValue()
+}
+
+// ListValue is a template value that evaluates to a list of T, where T is
+// one of the typed value interfaces: a List literal, an intrinsic such as
+// GetAZs, Split or Cidr, or any intrinsic wrapped in ListOf.
+type ListValue[T any] interface {
+	listValue(T)
+}
+
+// ObjectValue is a template value that evaluates to an object of type T, a
+// property type struct generated by codegen: a *T, or an intrinsic such as
+// If or FindInMap wrapped in ObjectOf.
+type ObjectValue[T any] interface {
+	// CloudFormationObject returns the object, or nil for an intrinsic.
+	CloudFormationObject() *T
+}
+
+// String is a string literal.
+type String string
+
+// Int is a number literal.
+type Int int
+
+// Double is a number literal that may have a fractional part.
+type Double float64
+
+// Bool is a boolean literal.
+type Bool bool
+
+// List is a list literal.
+//
+// Example:
+//
+//	List[StringValue]{String("a"), Ref{"B"}} → ["a", {"Ref": "B"}]
+type List[T any] []T
+
+// Items returns the elements of the list.
+func (l List[T]) Items() []any {
+	items := make([]any, len(l))
+	for i, item := range l {
+		items[i] = item
+	}
+	return items
+}
+
+// Strings returns a list literal of strings.
+func Strings(values ...string) List[StringValue] {
+	list := make(List[StringValue], len(values))
+	for i, v := range values {
+		list[i] = String(v)
+	}
+	return list
+}
+
+// ListOf wraps an intrinsic that evaluates to a list, such as a Ref to a
+// List<AWS::EC2::Subnet::Id> parameter. It encodes as the wrapped value.
+// Use GetAttList for a GetAtt of a list attribute.
+//
+// Example:
+//
+//	ListOf[StringValue]{Ref{"Subnets"}} → {"Ref": "Subnets"}
+type ListOf[T any] struct {
+	Value any
+}
+
+// Wrapped returns the wrapped value.
+func (l ListOf[T]) Wrapped() any {
+	return l.Value
+}
+
+// MarshalJSON serializes the wrapped value.
+func (l ListOf[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Value)
+}
+
+// MarshalYAML writes the wrapped value.
+func (l ListOf[T]) MarshalYAML() (any, error) {
+	return EncodeNode(l.Value, ShortFormFallbackOuter)
+}
+
+// ObjectOf wraps an intrinsic that evaluates to an object of type T. It
+// encodes as the wrapped value.
+//
+// Example:
+//
+//	ObjectOf[s3.BucketEncryption]{If{"Encrypt", &s3.BucketEncryption{...}, AWS_NO_VALUE}}
+type ObjectOf[T any] struct {
+	Value any
+}
+
+// CloudFormationObject returns nil; the object is only known once the
+// intrinsic is evaluated.
+func (ObjectOf[T]) CloudFormationObject() *T {
+	return nil
+}
+
+// Wrapped returns the wrapped value.
+func (o ObjectOf[T]) Wrapped() any {
+	return o.Value
+}
+
+// MarshalJSON serializes the wrapped value.
+func (o ObjectOf[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+
+// MarshalYAML writes the wrapped value.
+func (o ObjectOf[T]) MarshalYAML() (any, error) {
+	return EncodeNode(o.Value, ShortFormFallbackOuter)
+}
+
+// GetAttList is a GetAtt of a list attribute, such as the
+// CidrBlockAssociations of an AWS::EC2::VPC. GetAtt is a StringValue, so a
+// list attribute must go through GetAttList, which is a list of strings,
+// for Select and JoinList to accept it. It encodes as the GetAtt.
+//
+// Example:
+//
+//	JoinList(",", GetAttList{"Vpc", "CidrBlockAssociations"})
+type GetAttList struct {
+	LogicalName string
+	Attribute   string
+}
+
+// Wrapped returns the GetAtt.
+func (g GetAttList) Wrapped() any {
+	return GetAtt{LogicalName: g.LogicalName, Attribute: g.Attribute}
+}
+
+// MarshalJSON serializes to CloudFormation GetAtt syntax.
+func (g GetAttList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.Wrapped())
+}
+
+// MarshalYAML writes !GetAtt Resource.Attribute.
+func (g GetAttList) MarshalYAML() (any, error) {
+	return EncodeNode(g.Wrapped(), ShortFormFallbackOuter)
+}
+
+// JoinWithList is Fn::Join over a list value, such as a Ref to a list
+// parameter, rather than over a literal list of values like Join. It
+// encodes as the long-form map, which is also what Unmarshal decodes
+// such a join to.
+type JoinWithList struct {
+	Delimiter string
+	List      any
+}
+
+// Wrapped returns the long-form map.
+func (j JoinWithList) Wrapped() any {
+	return map[string]any{"Fn::Join": []any{j.Delimiter, j.List}}
+}
+
+// MarshalJSON serializes to CloudFormation Join syntax.
+func (j JoinWithList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Wrapped())
+}
+
+// MarshalYAML writes !Join [delimiter, list].
+func (j JoinWithList) MarshalYAML() (any, error) {
+	return EncodeNode(j, ShortFormFallbackOuter)
+}
+
+// JoinOf returns a Join of string values.
+//
+// Example:
+//
+//	JoinOf(":", String("arn"), AWS_PARTITION) → {"Fn::Join": [":", ["arn", {"Ref": "AWS::Partition"}]]}
+func JoinOf(delimiter string, values ...StringValue) Join {
+	items := make([]any, len(values))
+	for i, v := range values {
+		items[i] = v
+	}
+	return Join{Delimiter: delimiter, Values: items}
+}
+
+// JoinList returns a Join of a list of strings.
+//
+// Example:
+//
+//	JoinList(",", ListOf[StringValue]{Ref{"Subnets"}}) → {"Fn::Join": [",", {"Ref": "Subnets"}]}
+func JoinList(delimiter string, list ListValue[StringValue]) JoinWithList {
+	return JoinWithList{Delimiter: delimiter, List: list}
+}
+
+// SelectOf returns a Select of a string from a list of strings.
+func SelectOf(index int, list ListValue[StringValue]) Select {
+	return Select{Index: index, List: list}
+}
+
+// SplitOf returns a Split of a string into a list of strings.
+func SplitOf(delimiter string, source StringValue) Split {
+	return Split{Delimiter: delimiter, Source: source}
+}
+
+// IfOf returns an If whose branches have the same type.
+//
+// Example:
+//
+//	IfOf[StringValue]("IsProd", String("m5.large"), Ref{"InstanceType"})
+func IfOf[T any](condition string, ifTrue, ifFalse T) If {
+	return If{Condition: condition, ValueIfTrue: ifTrue, ValueIfFalse: ifFalse}
+}
+
+func (String) stringValue() {}
+func (Int) intValue()       {}
+func (Int) doubleValue()    {}
+func (Double) doubleValue() {}
+func (Bool) boolValue()     {}
+
+func (List[T]) listValue(T)              {}
+func (ListOf[T]) listValue(T)            {}
+func (GetAttList) listValue(StringValue) {}
+
+// Ref and If evaluate to whatever the parameter or branches hold.
+func (Ref) stringValue() {}
+func (Ref) intValue()    {}
+func (Ref) doubleValue() {}
+func (Ref) boolValue()   {}
+func (If) stringValue()  {}
+func (If) intValue()     {}
+func (If) doubleValue()  {}
+func (If) boolValue()    {}
+
+func (GetAtt) stringValue()               {}
+func (GetAttDynamic) stringValue()        {}
+func (Sub) stringValue()                  {}
+func (SubWithMap) stringValue()           {}
+func (Join) stringValue()                 {}
+func (JoinWithList) stringValue()         {}
+func (Select) stringValue()               {}
+func (Base64) stringValue()               {}
+func (ImportValue) stringValue()          {}
+func (ToJsonString) stringValue()         {}
+func (FindInMap) stringValue()            {}
+func (FindInMap) intValue()               {}
+func (FindInMap) doubleValue()            {}
+func (FindInMapWithDefault) stringValue() {}
+func (FindInMapWithDefault) intValue()    {}
+func (FindInMapWithDefault) doubleValue() {}
+func (Length) intValue()                  {}
+func (Length) doubleValue()               {}
+
+func (Condition) boolValue() {}
+func (Equals) boolValue()    {}
+func (And) boolValue()       {}
+func (Or) boolValue()        {}
+func (Not) boolValue()       {}
+
+func (GetAZs) listValue(StringValue) {}
+func (Split) listValue(StringValue)  {}
+func (Cidr) listValue(StringValue)   {}
@@ -0,0 +1,152 @@
+package intrinsics_test
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+// widget stands in for a property type struct generated by codegen.
+type widget struct {
+	Name intrinsics.StringValue
+}
+
+func (w *widget) CloudFormationObject() *widget { return w }
+
+// The typed value interfaces are checked at compile time.
+var (
+	_ intrinsics.StringValue = intrinsics.String("x")
+	_ intrinsics.StringValue = intrinsics.Ref{}
+	_ intrinsics.StringValue = intrinsics.GetAtt{}
+	_ intrinsics.StringValue = intrinsics.Sub{}
+	_ intrinsics.StringValue = intrinsics.Join{}
+	_ intrinsics.StringValue = intrinsics.JoinWithList{}
+	_ intrinsics.StringValue = intrinsics.Select{}
+	_ intrinsics.IntValue    = intrinsics.Int(1)
+	_ intrinsics.DoubleValue = intrinsics.Double(0.5)
+	_ intrinsics.DoubleValue = intrinsics.Int(1)
+	_ intrinsics.DoubleValue = intrinsics.Ref{}
+	_ intrinsics.IntValue    = intrinsics.Length{}
+	_ intrinsics.BoolValue   = intrinsics.Bool(true)
+	_ intrinsics.BoolValue   = intrinsics.Equals{}
+	_ intrinsics.BoolValue   = intrinsics.Condition{}
+
+	_ intrinsics.ListValue[intrinsics.StringValue] = intrinsics.Strings("a")
+	_ intrinsics.ListValue[intrinsics.StringValue] = intrinsics.GetAZs{}
+	_ intrinsics.ListValue[intrinsics.StringValue] = intrinsics.Split{}
+	_ intrinsics.ListValue[intrinsics.StringValue] = intrinsics.Cidr{}
+	_ intrinsics.ListValue[intrinsics.StringValue] = intrinsics.ListOf[intrinsics.StringValue]{}
+	_ intrinsics.ListValue[intrinsics.StringValue] = intrinsics.GetAttList{}
+	_ intrinsics.ListValue[intrinsics.IntValue]    = intrinsics.List[intrinsics.IntValue]{intrinsics.Int(1)}
+
+	_ intrinsics.ObjectValue[widget] = &widget{}
+	_ intrinsics.ObjectValue[widget] = intrinsics.ObjectOf[widget]{}
+)
+
+func TestTypedValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{
+			name:  "join",
+			value: intrinsics.JoinOf(":", intrinsics.String("arn"), intrinsics.AWS_PARTITION, intrinsics.GetAtt{LogicalName: "R", Attribute: "Name"}),
+			want:  `{"Fn::Join":[":",["arn",{"Ref":"AWS::Partition"},{"Fn::GetAtt":["R","Name"]}]]}`,
+		},
+		{
+			name:  "select",
+			value: intrinsics.SelectOf(0, intrinsics.GetAZs{}),
+			want:  `{"Fn::Select":[0,{"Fn::GetAZs":""}]}`,
+		},
+		{
+			name:  "select_list_ref",
+			value: intrinsics.SelectOf(1, intrinsics.ListOf[intrinsics.StringValue]{Value: intrinsics.Ref{LogicalName: "Subnets"}}),
+			want:  `{"Fn::Select":[1,{"Ref":"Subnets"}]}`,
+		},
+		{
+			name:  "join_list",
+			value: intrinsics.JoinList(",", intrinsics.ListOf[intrinsics.StringValue]{Value: intrinsics.Ref{LogicalName: "Subnets"}}),
+			want:  `{"Fn::Join":[",",{"Ref":"Subnets"}]}`,
+		},
+		{
+			name:  "join_list_attribute",
+			value: intrinsics.JoinList(",", intrinsics.GetAttList{LogicalName: "Vpc", Attribute: "CidrBlockAssociations"}),
+			want:  `{"Fn::Join":[",",{"Fn::GetAtt":["Vpc","CidrBlockAssociations"]}]}`,
+		},
+		{
+			name:  "select_list_attribute",
+			value: intrinsics.SelectOf(0, intrinsics.GetAttList{LogicalName: "Vpc", Attribute: "Ipv6CidrBlocks"}),
+			want:  `{"Fn::Select":[0,{"Fn::GetAtt":["Vpc","Ipv6CidrBlocks"]}]}`,
+		},
+		{
+			name:  "split",
+			value: intrinsics.SplitOf(",", intrinsics.ImportValue{ExportName: "ids"}),
+			want:  `{"Fn::Split":[",",{"Fn::ImportValue":"ids"}]}`,
+		},
+		{
+			name:  "if",
+			value: intrinsics.IfOf[intrinsics.IntValue]("IsProd", intrinsics.Int(3), intrinsics.Ref{LogicalName: "Count"}),
+			want:  `{"Fn::If":["IsProd",3,{"Ref":"Count"}]}`,
+		},
+		{
+			name:  "object",
+			value: intrinsics.List[intrinsics.ObjectValue[widget]]{&widget{Name: intrinsics.String("a")}},
+			want:  `[{"Name":"a"}]`,
+		},
+		{
+			name:  "object_of",
+			value: intrinsics.ObjectOf[widget]{Value: intrinsics.If{Condition: "IsProd", ValueIfTrue: &widget{Name: intrinsics.Ref{LogicalName: "N"}}, ValueIfFalse: intrinsics.AWS_NO_VALUE}},
+			want:  `{"Fn::If":["IsProd",{"Name":{"Ref":"N"}},{"Ref":"AWS::NoValue"}]}`,
+		},
+		{
+			name:  "double",
+			value: []any{intrinsics.Double(0.5), intrinsics.Double(2)},
+			want:  `[0.5,2]`,
+		},
+		{
+			name:  "literals",
+			value: []any{intrinsics.String("a"), intrinsics.Int(1), intrinsics.Bool(false), intrinsics.Strings("x", "y")},
+			want:  `["a",1,false,["x","y"]]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mustMarshal(t, tt.value)
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedValues_YAML(t *testing.T) {
+	value := intrinsics.SelectOf(0, intrinsics.ListOf[intrinsics.StringValue]{Value: intrinsics.Ref{LogicalName: "Subnets"}})
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "!Select [0, !Ref Subnets]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	data, err = yaml.Marshal(intrinsics.JoinList(",", intrinsics.GetAttList{LogicalName: "Vpc", Attribute: "CidrBlockAssociations"}))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "!Join [',', !GetAtt Vpc.CidrBlockAssociations]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	data, err = intrinsics.MarshalYAML(intrinsics.Strings("a", "b"), intrinsics.ShortFormNever)
+	if err != nil {
+		t.Fatalf("MarshalYAML failed: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(data)), "- a\n- b"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
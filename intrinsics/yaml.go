@@ -0,0 +1,388 @@
+package intrinsics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ShortFormPolicy controls when intrinsics are written as short-form YAML
+// tags such as !Ref and !GetAtt X.Y.
+//
+// A YAML node has at most one tag, so a function whose argument is itself
+// a short-form function, like Fn::Base64 over Fn::Sub, cannot use short
+// form for both. The policy picks which one falls back to long form.
+type ShortFormPolicy int
+
+const (
+	// ShortFormFallbackOuter writes the outer function in long form:
+	//
+	//	Fn::Base64: !Sub "..."
+	ShortFormFallbackOuter ShortFormPolicy = iota
+	// ShortFormFallbackInner writes the inner function in long form:
+	//
+	//	!Base64 {Fn::Sub: "..."}
+	ShortFormFallbackInner
+	// ShortFormNever writes every function in long form, like the JSON encoding.
+	ShortFormNever
+)
+
+// MarshalYAML encodes a value to YAML, writing intrinsics at any depth
+// according to the policy.
+func MarshalYAML(v any, policy ShortFormPolicy) ([]byte, error) {
+	node, err := EncodeNode(v, policy)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(node)
+}
+
+// EncodeNode converts a value to a YAML node, writing intrinsics at any
+// depth according to the policy. Maps are written with sorted keys.
+func EncodeNode(v any, policy ShortFormPolicy) (*yaml.Node, error) {
+	e := yamlEncoder{policy: policy}
+	return e.encode(v, policy != ShortFormNever)
+}
+
+type yamlEncoder struct {
+	policy ShortFormPolicy
+}
+
+// encode converts v to a node. short reports whether v itself, if it is
+// an intrinsic, may use short form; its arguments follow the policy.
+func (e yamlEncoder) encode(v any, short bool) (*yaml.Node, error) {
+	switch v := v.(type) {
+	case Ref:
+		return e.function("Ref", stringNode(v.LogicalName), short), nil
+	case Condition:
+		return e.function("Condition", stringNode(v.Name), short), nil
+	case GetAtt:
+		if short {
+			return e.function("Fn::GetAtt", stringNode(v.LogicalName+"."+v.Attribute), true), nil
+		}
+		return e.function("Fn::GetAtt", flowSequence(stringNode(v.LogicalName), stringNode(v.Attribute)), false), nil
+	case Sub:
+		return e.function("Fn::Sub", stringNode(v.String), short), nil
+	case SubWithMap:
+		return e.list("Fn::Sub", short, v.String, v.Variables)
+	case Join:
+		return e.list("Fn::Join", short, v.Delimiter, v.Values)
+	case JoinWithList:
+		return e.list("Fn::Join", short, v.Delimiter, v.List)
+	case Select:
+		return e.list("Fn::Select", short, v.Index, v.List)
+	case GetAZs:
+		return e.function("Fn::GetAZs", stringNode(v.Region), short), nil
+	case If:
+		return e.list("Fn::If", short, v.Condition, v.ValueIfTrue, v.ValueIfFalse)
+	case Equals:
+		return e.list("Fn::Equals", short, v.Value1, v.Value2)
+	case And:
+		return e.list("Fn::And", short, v.Conditions...)
+	case Or:
+		return e.list("Fn::Or", short, v.Conditions...)
+	case Not:
+		return e.list("Fn::Not", short, v.Condition)
+	case Base64:
+		return e.single("Fn::Base64", short, v.Value)
+	case ImportValue:
+		return e.single("Fn::ImportValue", short, v.ExportName)
+	case FindInMap:
+		return e.list("Fn::FindInMap", short, v.MapName, v.TopKey, v.SecondKey)
+	case Split:
+		return e.list("Fn::Split", short, v.Delimiter, v.Source)
+	case Cidr:
+		return e.list("Fn::Cidr", short, v.IPBlock, v.Count, v.CidrBits)
+	case Transform:
+		args := map[string]any{"Name": v.Name}
+		if v.Parameters != nil {
+			args["Parameters"] = v.Parameters
+		}
+		arg, err := e.encode(args, true)
+		if err != nil {
+			return nil, err
+		}
+		return e.function("Fn::Transform", arg, short), nil
+	case GetAttDynamic:
+		return e.list("Fn::GetAtt", short, v.LogicalName, v.Attribute)
+	case FindInMapWithDefault:
+		return e.list("Fn::FindInMap", short, v.MapName, v.TopKey, v.SecondKey, map[string]any{"DefaultValue": v.DefaultValue})
+	case Length:
+		return e.single("Fn::Length", short, v.Value)
+	case ToJsonString:
+		return e.single("Fn::ToJsonString", short, v.Value)
+	case ForEach:
+		// Loops have no short form; they are keys, not values.
+		arg, err := e.encode([]any{v.Identifier, v.Collection, v.Output}, true)
+		if err != nil {
+			return nil, err
+		}
+		return e.function(v.Key(), arg, false), nil
+	case Contains:
+		return e.list("Fn::Contains", short, v.List, v.Value)
+	case EachMemberEquals:
+		return e.list("Fn::EachMemberEquals", short, v.List, v.Value)
+	case EachMemberIn:
+		return e.list("Fn::EachMemberIn", short, v.StringsToCheck, v.StringsToMatch)
+	case RefAll:
+		return e.function("Fn::RefAll", stringNode(v.ParameterType), short), nil
+	case ValueOf:
+		return e.list("Fn::ValueOf", short, v.ParameterLogicalID, v.Attribute)
+	case ValueOfAll:
+		return e.list("Fn::ValueOfAll", short, v.ParameterType, v.Attribute)
+	case Rule:
+		m := map[string]any{"Assertions": v.Assertions}
+		if v.RuleCondition != nil {
+			m["RuleCondition"] = v.RuleCondition
+		}
+		return e.mapping([]string{"RuleCondition", "Assertions"}, m)
+	case []Assertion:
+		items := make([]any, len(v))
+		for i, a := range v {
+			items[i] = a
+		}
+		return e.encode(items, short)
+	case Assertion:
+		m := map[string]any{"Assert": v.Assert}
+		if v.AssertDescription != "" {
+			m["AssertDescription"] = v.AssertDescription
+		}
+		return e.mapping([]string{"Assert", "AssertDescription"}, m)
+	case Tag:
+		return e.mapping([]string{"Key", "Value"}, map[string]any{"Key": v.Key, "Value": v.Value})
+	case Output:
+		keys := []string{"Description", "Value", "Export", "Condition"}
+		m := map[string]any{"Value": v.Value}
+		if v.Description != "" {
+			m["Description"] = v.Description
+		}
+		if v.ExportName != nil {
+			m["Export"] = map[string]any{"Name": v.ExportName}
+		}
+		if v.Condition != "" {
+			m["Condition"] = v.Condition
+		}
+		return e.mapping(keys, m)
+
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return e.mapping(keys, v)
+
+	case interface{ Items() []any }:
+		return e.encode(v.Items(), short)
+	case interface{ Wrapped() any }:
+		return e.encode(v.Wrapped(), short)
+
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			child, err := e.encode(item, e.policy != ShortFormNever)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(v); err != nil {
+		return nil, fmt.Errorf("intrinsics: %w", err)
+	}
+	return node, nil
+}
+
+// function returns a node for an intrinsic with an encoded argument, as
+// a short-form tag or a single-key long-form mapping.
+func (e yamlEncoder) function(name string, arg *yaml.Node, short bool) *yaml.Node {
+	if short {
+		arg.Tag = "!" + strings.TrimPrefix(name, "Fn::")
+		if arg.Kind == yaml.ScalarNode && arg.Value == "" {
+			arg.Style = yaml.DoubleQuotedStyle
+		}
+		return arg
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{stringNode(name), arg}}
+}
+
+// list returns a node for an intrinsic whose argument is a list.
+func (e yamlEncoder) list(name string, short bool, args ...any) (*yaml.Node, error) {
+	arg, err := e.encode(args, true)
+	if err != nil {
+		return nil, err
+	}
+	if isFlat(arg) {
+		arg.Style = yaml.FlowStyle
+	}
+	return e.function(name, arg, short), nil
+}
+
+// single returns a node for an intrinsic whose argument is a single value,
+// which may itself be a short-form intrinsic.
+func (e yamlEncoder) single(name string, short bool, value any) (*yaml.Node, error) {
+	arg, err := e.encode(value, e.policy != ShortFormNever)
+	if err != nil {
+		return nil, err
+	}
+	if short && isShortForm(arg) {
+		if e.policy == ShortFormFallbackOuter {
+			return e.function(name, arg, false), nil
+		}
+		if arg, err = e.encode(value, false); err != nil {
+			return nil, err
+		}
+	}
+	return e.function(name, arg, short), nil
+}
+
+// mapping returns a mapping node with the given keys of m, in order.
+// Keys missing from m are skipped.
+func (e yamlEncoder) mapping(keys []string, m map[string]any) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, key := range keys {
+		value, ok := m[key]
+		if !ok {
+			continue
+		}
+		child, err := e.encode(value, e.policy != ShortFormNever)
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, stringNode(key), child)
+	}
+	return node, nil
+}
+
+func stringNode(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+func flowSequence(items ...*yaml.Node) *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle, Content: items}
+}
+
+// isShortForm reports whether a node carries a short-form intrinsic tag.
+func isShortForm(node *yaml.Node) bool {
+	return strings.HasPrefix(node.Tag, "!") && !strings.HasPrefix(node.Tag, "!!")
+}
+
+// isFlat reports whether a sequence holds only scalars, so it reads well
+// in flow style.
+func isFlat(node *yaml.Node) bool {
+	for _, child := range node.Content {
+		if child.Kind != yaml.ScalarNode {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalYAML writes !Ref.
+func (r Ref) MarshalYAML() (any, error) { return EncodeNode(r, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !GetAtt Resource.Attribute.
+func (g GetAtt) MarshalYAML() (any, error) { return EncodeNode(g, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Sub.
+func (s Sub) MarshalYAML() (any, error) { return EncodeNode(s, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Sub with a variable map.
+func (s SubWithMap) MarshalYAML() (any, error) { return EncodeNode(s, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Join.
+func (j Join) MarshalYAML() (any, error) { return EncodeNode(j, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Select.
+func (s Select) MarshalYAML() (any, error) { return EncodeNode(s, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !GetAZs.
+func (g GetAZs) MarshalYAML() (any, error) { return EncodeNode(g, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !If.
+func (i If) MarshalYAML() (any, error) { return EncodeNode(i, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Equals.
+func (e Equals) MarshalYAML() (any, error) { return EncodeNode(e, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !And.
+func (a And) MarshalYAML() (any, error) { return EncodeNode(a, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Or.
+func (o Or) MarshalYAML() (any, error) { return EncodeNode(o, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Not.
+func (n Not) MarshalYAML() (any, error) { return EncodeNode(n, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Base64, or Fn::Base64 over a short-form argument.
+func (b Base64) MarshalYAML() (any, error) { return EncodeNode(b, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !ImportValue, or Fn::ImportValue over a short-form argument.
+func (i ImportValue) MarshalYAML() (any, error) { return EncodeNode(i, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !FindInMap.
+func (f FindInMap) MarshalYAML() (any, error) { return EncodeNode(f, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Split.
+func (s Split) MarshalYAML() (any, error) { return EncodeNode(s, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Cidr.
+func (c Cidr) MarshalYAML() (any, error) { return EncodeNode(c, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Condition.
+func (c Condition) MarshalYAML() (any, error) { return EncodeNode(c, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Transform with Name and Parameters.
+func (t Transform) MarshalYAML() (any, error) { return EncodeNode(t, ShortFormFallbackOuter) }
+
+// MarshalYAML writes a Key and Value mapping.
+func (t Tag) MarshalYAML() (any, error) { return EncodeNode(t, ShortFormFallbackOuter) }
+
+// MarshalYAML writes an output with Description, Value, Export and Condition.
+func (o Output) MarshalYAML() (any, error) { return EncodeNode(o, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !GetAtt [Resource, attribute].
+func (g GetAttDynamic) MarshalYAML() (any, error) { return EncodeNode(g, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !FindInMap with a DefaultValue mapping.
+func (f FindInMapWithDefault) MarshalYAML() (any, error) {
+	return EncodeNode(f, ShortFormFallbackOuter)
+}
+
+// MarshalYAML writes !Length.
+func (l Length) MarshalYAML() (any, error) { return EncodeNode(l, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !ToJsonString.
+func (t ToJsonString) MarshalYAML() (any, error) { return EncodeNode(t, ShortFormFallbackOuter) }
+
+// MarshalYAML writes the single-entry Fn::ForEach::Name mapping.
+func (f ForEach) MarshalYAML() (any, error) { return EncodeNode(f, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !Contains.
+func (c Contains) MarshalYAML() (any, error) { return EncodeNode(c, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !EachMemberEquals.
+func (e EachMemberEquals) MarshalYAML() (any, error) { return EncodeNode(e, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !EachMemberIn.
+func (e EachMemberIn) MarshalYAML() (any, error) { return EncodeNode(e, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !RefAll.
+func (r RefAll) MarshalYAML() (any, error) { return EncodeNode(r, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !ValueOf.
+func (v ValueOf) MarshalYAML() (any, error) { return EncodeNode(v, ShortFormFallbackOuter) }
+
+// MarshalYAML writes !ValueOfAll.
+func (v ValueOfAll) MarshalYAML() (any, error) { return EncodeNode(v, ShortFormFallbackOuter) }
+
+// MarshalYAML writes a rule with RuleCondition and Assertions.
+func (r Rule) MarshalYAML() (any, error) { return EncodeNode(r, ShortFormFallbackOuter) }
+
+// MarshalYAML writes an assertion with Assert and AssertDescription.
+func (a Assertion) MarshalYAML() (any, error) { return EncodeNode(a, ShortFormFallbackOuter) }
@@ -0,0 +1,107 @@
+package intrinsics_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+func TestMarshalYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"ref", intrinsics.Ref{LogicalName: "MyBucket"}, "!Ref MyBucket"},
+		{"getatt", intrinsics.GetAtt{LogicalName: "MyRole", Attribute: "Arn"}, "!GetAtt MyRole.Arn"},
+		{"sub", intrinsics.Sub{String: "${AWS::Region}-x"}, "!Sub ${AWS::Region}-x"},
+		{"getazs", intrinsics.GetAZs{}, `!GetAZs ""`},
+		{"condition", intrinsics.Condition{Name: "IsProd"}, "!Condition IsProd"},
+		{"select", intrinsics.Select{Index: 0, List: intrinsics.GetAZs{Region: "us-east-1"}}, "!Select [0, !GetAZs us-east-1]"},
+		{"if", intrinsics.If{Condition: "Prod", ValueIfTrue: "big", ValueIfFalse: intrinsics.AWS_NO_VALUE}, "!If [Prod, big, !Ref 'AWS::NoValue']"},
+		{"equals", intrinsics.Equals{Value1: intrinsics.Ref{LogicalName: "Env"}, Value2: "prod"}, "!Equals [!Ref Env, prod]"},
+		{"not", intrinsics.Not{Condition: intrinsics.Condition{Name: "A"}}, "!Not [!Condition A]"},
+		{"join", intrinsics.Join{Delimiter: ",", Values: []any{"a", intrinsics.Ref{LogicalName: "B"}}}, "!Join\n- ','\n- - a\n  - !Ref B"},
+		{"sub_map", intrinsics.SubWithMap{String: "${B}", Variables: map[string]any{"B": intrinsics.Ref{LogicalName: "Bucket"}}}, "!Sub\n- ${B}\n- B: !Ref Bucket"},
+		{"base64_nested", intrinsics.Base64{Value: intrinsics.Sub{String: "echo ${X}"}}, "Fn::Base64: !Sub echo ${X}"},
+		{"base64_plain", intrinsics.Base64{Value: "data"}, "!Base64 data"},
+		{"getatt_dynamic", intrinsics.GetAttDynamic{LogicalName: "B", Attribute: intrinsics.Ref{LogicalName: "Attr"}}, "!GetAtt [B, !Ref Attr]"},
+		{"findinmap_default", intrinsics.FindInMapWithDefault{MapName: "M", TopKey: "a", SecondKey: "b", DefaultValue: "x"}, "!FindInMap\n- M\n- a\n- b\n- DefaultValue: x"},
+		{"length", intrinsics.Length{Value: intrinsics.Ref{LogicalName: "L"}}, "Fn::Length: !Ref L"},
+		{"contains", intrinsics.Contains{List: []any{"a"}, Value: intrinsics.Ref{LogicalName: "P"}}, "!Contains\n- - a\n- !Ref P"},
+		{"refall", intrinsics.RefAll{ParameterType: "AWS::EC2::VPC::Id"}, "!RefAll AWS::EC2::VPC::Id"},
+		{"valueof", intrinsics.ValueOf{ParameterLogicalID: "Vpc", Attribute: "Tags.Env"}, "!ValueOf [Vpc, Tags.Env]"},
+		{"foreach", intrinsics.ForEach{LoopName: "L", Identifier: "X", Collection: []any{"a"}, Output: map[string]any{"${X}": "v"}}, "Fn::ForEach::L:\n    - X\n    - - a\n    - ${X}: v"},
+		{"rule", intrinsics.Rule{Assertions: []intrinsics.Assertion{{Assert: intrinsics.Condition{Name: "A"}, AssertDescription: "d"}}}, "Assertions:\n    - Assert: !Condition A\n      AssertDescription: d"},
+		{"tag", intrinsics.Tag{Key: "Env", Value: intrinsics.Ref{LogicalName: "Env"}}, "Key: Env\nValue: !Ref Env"},
+		{"output", intrinsics.Output{Value: intrinsics.GetAtt{LogicalName: "R", Attribute: "Arn"}, Description: "d", ExportName: "x"}, "Description: d\nValue: !GetAtt R.Arn\nExport:\n    Name: x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := yaml.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if got := strings.TrimSpace(string(data)); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalYAML_Policy(t *testing.T) {
+	value := intrinsics.ImportValue{ExportName: intrinsics.Sub{String: "${Env}-vpc"}}
+	tests := []struct {
+		policy intrinsics.ShortFormPolicy
+		want   string
+	}{
+		{intrinsics.ShortFormFallbackOuter, "Fn::ImportValue: !Sub ${Env}-vpc"},
+		{intrinsics.ShortFormFallbackInner, "!ImportValue\nFn::Sub: ${Env}-vpc"},
+		{intrinsics.ShortFormNever, "Fn::ImportValue:\n    Fn::Sub: ${Env}-vpc"},
+	}
+	for _, tt := range tests {
+		data, err := intrinsics.MarshalYAML(value, tt.policy)
+		if err != nil {
+			t.Fatalf("MarshalYAML failed: %v", err)
+		}
+		if got := strings.TrimSpace(string(data)); got != tt.want {
+			t.Errorf("policy %d: got:\n%s\nwant:\n%s", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestMarshalYAML_RoundTrip(t *testing.T) {
+	value := map[string]any{
+		"Arn":     intrinsics.Join{Values: []any{"arn:", intrinsics.AWS_PARTITION, ":s3:::", intrinsics.Ref{LogicalName: "B"}}},
+		"AZ":      intrinsics.Select{Index: 1, List: intrinsics.GetAZs{}},
+		"Cidr":    intrinsics.Cidr{IPBlock: intrinsics.GetAtt{LogicalName: "Vpc", Attribute: "CidrBlock"}, Count: 6, CidrBits: 8},
+		"Cond":    intrinsics.Or{Conditions: []any{intrinsics.Condition{Name: "A"}, intrinsics.And{Conditions: []any{intrinsics.Condition{Name: "B"}}}}},
+		"Map":     intrinsics.FindInMap{MapName: "M", TopKey: intrinsics.AWS_REGION, SecondKey: "Ami"},
+		"Nested":  intrinsics.Base64{Value: intrinsics.Join{Delimiter: "", Values: []any{"a", intrinsics.Sub{String: "${X}"}}}},
+		"Split":   intrinsics.Split{Delimiter: ",", Source: intrinsics.ImportValue{ExportName: "subnets"}},
+		"Include": intrinsics.Transform{Name: "AWS::Include", Parameters: map[string]any{"Location": "s3://b/k"}},
+		"Plain":   []any{"true", 3, 1.5, nil},
+		"Size":    intrinsics.FindInMapWithDefault{MapName: "M", TopKey: intrinsics.Ref{LogicalName: "Env"}, SecondKey: "Size", DefaultValue: intrinsics.Length{Value: intrinsics.Ref{LogicalName: "L"}}},
+		"Json":    intrinsics.ToJsonString{Value: map[string]any{"A": intrinsics.GetAttDynamic{LogicalName: "R", Attribute: intrinsics.Ref{LogicalName: "P"}}}},
+		"Rule":    intrinsics.EachMemberIn{StringsToCheck: intrinsics.ValueOfAll{ParameterType: "T", Attribute: "VpcId"}, StringsToMatch: intrinsics.RefAll{ParameterType: "AWS::EC2::VPC::Id"}},
+	}
+
+	for _, policy := range []intrinsics.ShortFormPolicy{intrinsics.ShortFormFallbackOuter, intrinsics.ShortFormFallbackInner, intrinsics.ShortFormNever} {
+		data, err := intrinsics.MarshalYAML(value, policy)
+		if err != nil {
+			t.Fatalf("MarshalYAML failed: %v", err)
+		}
+		got, err := intrinsics.UnmarshalYAML(data)
+		if err != nil {
+			t.Fatalf("UnmarshalYAML failed: %v\n%s", err, data)
+		}
+		if !reflect.DeepEqual(got, value) {
+			t.Errorf("policy %d: got %#v, want %#v\n%s", policy, got, value, data)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Package jsonschema generates JSON Schema (draft 2020-12) for complete
+// CloudFormation templates from the resource specification.
+//
+// The schema constrains each resource's Properties by its Type, accepts
+// intrinsic functions wherever a value is expected and fills in allowed
+// values from the enums package:
+//
+//	cfSpec, _ := spec.FetchSpec(nil)
+//	data, err := jsonschema.Generate(cfSpec, nil)
+//	os.WriteFile("cloudformation.schema.json", data, 0644)
+//
+// The result can be used with the YAML language server, for example in
+// VS Code's "yaml.schemas" setting. Short-form intrinsic tags (!Ref, !Sub)
+// are YAML syntax and must be declared in "yaml.customTags".
+package jsonschema
@@ -0,0 +1,358 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/lex00/cloudformation-schema-go/enums"
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+// SchemaURI is the JSON Schema dialect of generated schemas.
+const SchemaURI = "https://json-schema.org/draft/2020-12/schema"
+
+// Options configures Generate.
+type Options struct {
+	// ID is the "$id" of the generated schema. Omitted if empty.
+	ID string
+	// StrictEnums restricts string properties with an enums mapping to the
+	// allowed values. By default the values are only offered as "examples"
+	// for completion, because enums mappings are keyed by service and
+	// property name and can be too broad for some property types.
+	StrictEnums bool
+}
+
+// intrinsicFunctions are the keys of intrinsic function objects.
+var intrinsicFunctions = []string{
+	"Condition",
+	"Fn::And",
+	"Fn::Base64",
+	"Fn::Cidr",
+	"Fn::Equals",
+	"Fn::FindInMap",
+	"Fn::GetAZs",
+	"Fn::GetAtt",
+	"Fn::If",
+	"Fn::ImportValue",
+	"Fn::Join",
+	"Fn::Length",
+	"Fn::Not",
+	"Fn::Or",
+	"Fn::Select",
+	"Fn::Split",
+	"Fn::Sub",
+	"Fn::ToJsonString",
+	"Fn::Transform",
+	"Ref",
+}
+
+// parameterTypes are the non-SSM parameter types.
+var parameterTypes = []string{
+	"AWS::EC2::AvailabilityZone::Name",
+	"AWS::EC2::Image::Id",
+	"AWS::EC2::Instance::Id",
+	"AWS::EC2::KeyPair::KeyName",
+	"AWS::EC2::SecurityGroup::GroupName",
+	"AWS::EC2::SecurityGroup::Id",
+	"AWS::EC2::Subnet::Id",
+	"AWS::EC2::VPC::Id",
+	"AWS::EC2::Volume::Id",
+	"AWS::Route53::HostedZone::Id",
+	"CommaDelimitedList",
+	"List<AWS::EC2::AvailabilityZone::Name>",
+	"List<AWS::EC2::Image::Id>",
+	"List<AWS::EC2::Instance::Id>",
+	"List<AWS::EC2::SecurityGroup::GroupName>",
+	"List<AWS::EC2::SecurityGroup::Id>",
+	"List<AWS::EC2::Subnet::Id>",
+	"List<AWS::EC2::VPC::Id>",
+	"List<AWS::EC2::Volume::Id>",
+	"List<AWS::Route53::HostedZone::Id>",
+	"List<Number>",
+	"Number",
+	"String",
+}
+
+// object is a JSON Schema object. encoding/json sorts its keys, which
+// keeps the output deterministic.
+type object = map[string]any
+
+// Generate returns the JSON Schema for templates using the spec's types,
+// as indented JSON. If opts is nil, default options are used.
+// A spec with structural errors is rejected with a *spec.CheckError.
+func Generate(p spec.SchemaProvider, opts *Options) ([]byte, error) {
+	s, err := spec.Materialize(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := spec.Verify(s); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &Options{}
+	}
+	g := &generator{spec: s, opts: opts, defs: object{}}
+	return json.MarshalIndent(g.schema(), "", "  ")
+}
+
+type generator struct {
+	spec *spec.Spec
+	opts *Options
+	defs object
+}
+
+func ref(name string) object {
+	return object{"$ref": "#/$defs/" + name}
+}
+
+// orIntrinsic allows an intrinsic function in place of the schema.
+func orIntrinsic(schema object) object {
+	return object{"anyOf": []any{schema, ref("Intrinsic")}}
+}
+
+func (g *generator) schema() object {
+	g.addBaseDefinitions()
+
+	resourceTypes := g.spec.ResourceTypeNames()
+	sort.Strings(resourceTypes)
+	var branches []any
+	for _, name := range resourceTypes {
+		branches = append(branches, g.resourceBranch(name))
+	}
+	for _, name := range g.spec.PropertyTypeNames() {
+		g.defs[name] = g.propertyTypeSchema(name)
+	}
+
+	g.defs["Resource"] = object{
+		"type":     "object",
+		"required": []string{"Type"},
+		"properties": object{
+			"Type": object{
+				"type": "string",
+				"anyOf": []any{
+					object{"enum": resourceTypes},
+					object{"pattern": "^Custom::[A-Za-z0-9_@-]+$"},
+				},
+			},
+			"Properties":          object{"type": "object"},
+			"DependsOn":           object{"anyOf": []any{object{"type": "string"}, object{"type": "array", "items": object{"type": "string"}}}},
+			"Condition":           object{"type": "string"},
+			"DeletionPolicy":      object{"enum": []string{"Delete", "Retain", "RetainExceptOnCreate", "Snapshot"}},
+			"UpdateReplacePolicy": object{"enum": []string{"Delete", "Retain", "Snapshot"}},
+			"Metadata":            object{"type": "object"},
+			"CreationPolicy":      object{"type": "object"},
+			"UpdatePolicy":        object{"type": "object"},
+		},
+		"additionalProperties": false,
+		"allOf":                branches,
+	}
+
+	schema := object{
+		"$schema": SchemaURI,
+		"title":   "AWS CloudFormation template",
+		"type":    "object",
+		"properties": object{
+			"AWSTemplateFormatVersion": object{"enum": []string{"2010-09-09"}},
+			"Description":              object{"type": "string"},
+			"Metadata":                 object{"type": "object"},
+			"Transform":                object{"anyOf": []any{object{"type": "string"}, object{"type": "array", "items": object{"type": "string"}}, object{"type": "object"}}},
+			"Parameters":               object{"type": "object", "additionalProperties": ref("Parameter")},
+			"Mappings":                 object{"type": "object", "additionalProperties": object{"type": "object"}},
+			"Conditions":               object{"type": "object", "additionalProperties": ref("Intrinsic")},
+			"Rules":                    object{"type": "object"},
+			"Resources":                object{"type": "object", "minProperties": 1, "additionalProperties": ref("Resource")},
+			"Outputs":                  object{"type": "object", "additionalProperties": ref("Output")},
+		},
+		"required":             []string{"Resources"},
+		"additionalProperties": false,
+		"$defs":                g.defs,
+	}
+	if g.spec.ResourceSpecificationVersion != "" {
+		schema["$comment"] = "Generated from CloudFormation resource specification " + g.spec.ResourceSpecificationVersion
+	}
+	if g.opts.ID != "" {
+		schema["$id"] = g.opts.ID
+	}
+	return schema
+}
+
+// addBaseDefinitions adds intrinsics, primitives, parameters and outputs.
+func (g *generator) addBaseDefinitions() {
+	g.defs["Intrinsic"] = object{
+		"type":          "object",
+		"minProperties": 1,
+		"maxProperties": 1,
+		"propertyNames": object{"enum": intrinsicFunctions},
+	}
+
+	integer := object{"anyOf": []any{object{"type": "integer"}, object{"type": "string", "pattern": "^-?[0-9]+$"}}}
+	g.defs["String"] = orIntrinsic(object{"type": "string"})
+	g.defs["Integer"] = orIntrinsic(integer)
+	g.defs["Long"] = orIntrinsic(integer)
+	g.defs["Double"] = orIntrinsic(object{"anyOf": []any{object{"type": "number"}, object{"type": "string", "pattern": "^-?[0-9]+(\\.[0-9]+)?([eE][-+]?[0-9]+)?$"}}})
+	g.defs["Boolean"] = orIntrinsic(object{"anyOf": []any{object{"type": "boolean"}, object{"enum": []string{"true", "false"}}}})
+	g.defs["Timestamp"] = orIntrinsic(object{"type": "string"})
+	g.defs["Json"] = object{"type": []string{"object", "string"}}
+
+	g.defs["Parameter"] = object{
+		"type":     "object",
+		"required": []string{"Type"},
+		"properties": object{
+			"Type": object{
+				"type": "string",
+				"anyOf": []any{
+					object{"enum": parameterTypes},
+					object{"pattern": "^AWS::SSM::Parameter::Value<.+>$"},
+					object{"const": "AWS::SSM::Parameter::Name"},
+				},
+			},
+			"Default":               object{},
+			"Description":           object{"type": "string"},
+			"AllowedValues":         object{"type": "array"},
+			"AllowedPattern":        object{"type": "string"},
+			"ConstraintDescription": object{"type": "string"},
+			"MinLength":             object{"type": "integer"},
+			"MaxLength":             object{"type": "integer"},
+			"MinValue":              object{"type": "number"},
+			"MaxValue":              object{"type": "number"},
+			"NoEcho":                object{"type": []string{"boolean", "string"}},
+		},
+		"additionalProperties": false,
+	}
+
+	g.defs["Output"] = object{
+		"type":     "object",
+		"required": []string{"Value"},
+		"properties": object{
+			"Description": object{"type": "string"},
+			"Value":       object{},
+			"Condition":   object{"type": "string"},
+			"Export": object{
+				"type":                 "object",
+				"required":             []string{"Name"},
+				"properties":           object{"Name": object{}},
+				"additionalProperties": false,
+			},
+		},
+		"additionalProperties": false,
+	}
+}
+
+// resourceBranch adds the definition of a resource type's Properties and
+// returns the if/then branch selecting it by Type.
+func (g *generator) resourceBranch(name string) object {
+	rt := g.spec.ResourceTypes[name]
+	g.defs[name] = g.propertiesSchema(name, rt.Documentation, rt.Properties)
+	if spec.ResourceTypeDeprecation(name) != nil {
+		g.defs[name].(object)["deprecated"] = true
+	}
+
+	then := object{"properties": object{"Properties": ref(name)}}
+	if len(rt.GetRequiredProperties()) > 0 {
+		then["required"] = []string{"Properties"}
+	}
+	return object{
+		"if": object{
+			"properties": object{"Type": object{"const": name}},
+			"required":   []string{"Type"},
+		},
+		"then": then,
+	}
+}
+
+// propertyTypeSchema returns the definition of a property type. Property
+// type values may also be intrinsics such as Fn::If.
+func (g *generator) propertyTypeSchema(name string) object {
+	pt := g.spec.PropertyTypes[name]
+	return orIntrinsic(g.propertiesSchema(name, pt.Documentation, pt.Properties))
+}
+
+// propertiesSchema returns an object schema for a set of properties.
+// owner is the resource or property type declaring them.
+func (g *generator) propertiesSchema(owner, documentation string, props map[string]spec.Property) object {
+	properties := object{}
+	var required []string
+	for name, prop := range props {
+		properties[name] = g.propertySchema(owner, name, prop)
+		if prop.Required {
+			required = append(required, name)
+		}
+	}
+	schema := object{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	if documentation != "" {
+		schema["description"] = documentation
+	}
+	return schema
+}
+
+// propertySchema returns the schema of a property value.
+func (g *generator) propertySchema(owner, name string, prop spec.Property) object {
+	resourceType, _ := spec.ParsePropertyTypeName(owner)
+
+	var schema object
+	switch {
+	case prop.IsList():
+		schema = orIntrinsic(object{"type": "array", "items": g.itemSchema(resourceType, name, prop)})
+	case prop.IsMap():
+		schema = orIntrinsic(object{"type": "object", "additionalProperties": g.itemSchema(resourceType, name, prop)})
+	case prop.PrimitiveType != "":
+		schema = g.primitiveSchema(resourceType, name, prop.PrimitiveType)
+	default:
+		schema = g.typeRef(resourceType, prop.Type)
+	}
+
+	if prop.Documentation != "" {
+		// Sibling keywords of $ref and anyOf apply in draft 2020-12.
+		schema["description"] = prop.Documentation
+	}
+	if spec.PropertyDeprecation(owner, name) != nil {
+		schema["deprecated"] = true
+	}
+	return schema
+}
+
+// itemSchema returns the schema of list or map items.
+func (g *generator) itemSchema(resourceType, name string, prop spec.Property) object {
+	if prop.PrimitiveItemType != "" {
+		return g.primitiveSchema(resourceType, name, prop.PrimitiveItemType)
+	}
+	return g.typeRef(resourceType, prop.ItemType)
+}
+
+// primitiveSchema returns the schema of a primitive value, with enum values
+// from the enums package for string properties that have a mapping.
+func (g *generator) primitiveSchema(resourceType, name, primitive string) object {
+	if primitive == "String" {
+		service := enums.ServiceForCloudFormation(spec.ServiceName(resourceType))
+		if enumName := enums.GetEnumForProperty(service, name); enumName != "" {
+			if values := enums.GetAllowedValues(service, enumName); len(values) > 0 {
+				if g.opts.StrictEnums {
+					return orIntrinsic(object{"type": "string", "enum": values})
+				}
+				return object{"$ref": "#/$defs/String", "examples": values}
+			}
+		}
+	}
+	if _, ok := g.defs[primitive]; !ok {
+		return object{}
+	}
+	return ref(primitive)
+}
+
+// typeRef returns a reference to a property type, or an unconstrained
+// schema if the spec does not define it.
+func (g *generator) typeRef(resourceType, typeName string) object {
+	fullName, pt := g.spec.ResolvePropertyType(resourceType, typeName)
+	if pt == nil {
+		return object{}
+	}
+	return ref(fullName)
+}
@@ -0,0 +1,141 @@
+package jsonschema_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/jsonschema"
+	"github.com/lex00/cloudformation-schema-go/specembed"
+)
+
+func generate(t *testing.T, opts *jsonschema.Options) map[string]any {
+	t.Helper()
+	s, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("loading snapshot: %v", err)
+	}
+	data, err := jsonschema.Generate(s, opts)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid schema JSON: %v", err)
+	}
+	return schema
+}
+
+// lookup follows a path of object keys and array indexes.
+func lookup(t *testing.T, v any, path ...any) any {
+	t.Helper()
+	for _, p := range path {
+		switch key := p.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				t.Fatalf("expected object at %v", key)
+			}
+			v = m[key]
+		case int:
+			a, ok := v.([]any)
+			if !ok || key >= len(a) {
+				t.Fatalf("expected array with index %d", key)
+			}
+			v = a[key]
+		}
+	}
+	return v
+}
+
+func TestGenerate_Structure(t *testing.T) {
+	schema := generate(t, &jsonschema.Options{ID: "https://example.com/cfn.json"})
+
+	if schema["$schema"] != jsonschema.SchemaURI {
+		t.Errorf("$schema = %v", schema["$schema"])
+	}
+	if schema["$id"] != "https://example.com/cfn.json" {
+		t.Errorf("$id = %v", schema["$id"])
+	}
+	if got := lookup(t, schema, "properties", "Resources", "additionalProperties", "$ref"); got != "#/$defs/Resource" {
+		t.Errorf("Resources items = %v", got)
+	}
+
+	// Each resource type selects its Properties schema by Type.
+	branches := lookup(t, schema, "$defs", "Resource", "allOf").([]any)
+	var bucket map[string]any
+	for _, b := range branches {
+		if lookup(t, b, "if", "properties", "Type", "const") == "AWS::S3::Bucket" {
+			bucket = b.(map[string]any)
+		}
+	}
+	if bucket == nil {
+		t.Fatal("expected an if/then branch for AWS::S3::Bucket")
+	}
+	if got := lookup(t, bucket, "then", "properties", "Properties", "$ref"); got != "#/$defs/AWS::S3::Bucket" {
+		t.Errorf("then branch = %v", got)
+	}
+
+	props := lookup(t, schema, "$defs", "AWS::S3::Bucket", "properties")
+	if got := lookup(t, props, "BucketName", "$ref"); got != "#/$defs/String" {
+		t.Errorf("BucketName = %v", got)
+	}
+	if got := lookup(t, props, "Tags", "anyOf", 0, "items", "$ref"); got != "#/$defs/Tag" {
+		t.Errorf("Tags items = %v", got)
+	}
+	if got := lookup(t, props, "Tags", "anyOf", 1, "$ref"); got != "#/$defs/Intrinsic" {
+		t.Errorf("expected lists to accept intrinsics, got %v", got)
+	}
+	if got := lookup(t, schema, "$defs", "AWS::S3::Bucket", "additionalProperties"); got != false {
+		t.Errorf("expected unknown properties to be rejected, got %v", got)
+	}
+
+	if got := lookup(t, props, "AccessControl", "deprecated"); got != true {
+		t.Errorf("expected AccessControl to be deprecated, got %v", got)
+	}
+	if got := lookup(t, schema, "$defs", "AWS::SDB::Domain", "deprecated"); got != true {
+		t.Errorf("expected AWS::SDB::Domain to be deprecated, got %v", got)
+	}
+
+	// Types with required properties require Properties.
+	for _, b := range branches {
+		if lookup(t, b, "if", "properties", "Type", "const") == "AWS::IAM::Role" {
+			if got := lookup(t, b, "then", "required"); !reflect.DeepEqual(got, []any{"Properties"}) {
+				t.Errorf("AWS::IAM::Role then.required = %v", got)
+			}
+		}
+	}
+}
+
+func TestGenerate_Enums(t *testing.T) {
+	runtime := []string{"$defs", "AWS::Lambda::Function", "properties", "Runtime"}
+	path := make([]any, len(runtime))
+	for i, p := range runtime {
+		path[i] = p
+	}
+
+	loose := lookup(t, generate(t, nil), path...).(map[string]any)
+	examples, ok := loose["examples"].([]any)
+	if !ok || len(examples) < 10 {
+		t.Errorf("expected Runtime examples, got %v", loose)
+	}
+
+	strict := lookup(t, generate(t, &jsonschema.Options{StrictEnums: true}), path...)
+	values, ok := lookup(t, strict, "anyOf", 0, "enum").([]any)
+	if !ok || len(values) != len(examples) {
+		t.Errorf("expected strict Runtime enum, got %v", strict)
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	s, err := specembed.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := jsonschema.Generate(s, nil)
+	b, _ := jsonschema.Generate(s, nil)
+	if !bytes.Equal(a, b) {
+		t.Error("expected identical output for the same spec")
+	}
+}
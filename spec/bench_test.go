@@ -0,0 +1,72 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+	"github.com/lex00/cloudformation-schema-go/specembed"
+)
+
+// benchmarkSpec returns the embedded snapshot of the published spec.
+func benchmarkSpec(b *testing.B) *spec.Spec {
+	b.Helper()
+	s, err := specembed.Load()
+	if err != nil {
+		b.Fatalf("loading snapshot: %v", err)
+	}
+	return s
+}
+
+func BenchmarkLoad_JSON(b *testing.B) {
+	data, err := json.Marshal(benchmarkSpec(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var s spec.Spec
+		if err := json.Unmarshal(data, &s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoad_Binary(b *testing.B) {
+	data, err := benchmarkSpec(b).MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var s spec.Spec
+		if err := s.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkLoad_BinaryLazy measures the common CLI case of opening the
+// spec and looking up a single resource type.
+func BenchmarkLoad_BinaryLazy(b *testing.B) {
+	data, err := benchmarkSpec(b).MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lazy, err := spec.OpenBinary(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if lazy.GetResourceType("AWS::S3::Bucket") == nil {
+			b.Fatal("resource type not found")
+		}
+	}
+}
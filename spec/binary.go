@@ -0,0 +1,543 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"sort"
+	"sync"
+)
+
+// BinaryFormatVersion is the version of the binary spec encoding.
+// It changes whenever the encoding of the spec types changes; data
+// written with another version is rejected with ErrBinaryFormat.
+const BinaryFormatVersion = 2
+
+// binaryMagic starts every binary-encoded spec.
+const binaryMagic = "CFNSPECB"
+
+// ErrBinaryFormat is returned for data that is not a binary spec of the
+// current BinaryFormatVersion, or that fails its checksum.
+var ErrBinaryFormat = errors.New("invalid binary spec")
+
+// MarshalBinary encodes the spec in a compact binary format.
+//
+// The encoding is deterministic: a spec always encodes to the same bytes.
+// It starts with a header carrying BinaryFormatVersion and
+// ResourceSpecificationVersion, followed by an index of type names and
+// one independently decodable payload per type, so OpenBinary can decode
+// types on demand.
+func (s *Spec) MarshalBinary() ([]byte, error) {
+	var index, payloads bytes.Buffer
+
+	writeSection := func(names []string, encode func(w *binaryWriter, name string)) {
+		putUvarint(&index, uint64(len(names)))
+		for _, name := range names {
+			w := &binaryWriter{}
+			encode(w, name)
+			putString(&index, name)
+			putUvarint(&index, uint64(w.buf.Len()))
+			payloads.Write(w.buf.Bytes())
+		}
+	}
+	writeSection(sortedKeys(s.ResourceTypes), func(w *binaryWriter, name string) {
+		w.resourceType(s.ResourceTypes[name])
+	})
+	writeSection(sortedKeys(s.PropertyTypes), func(w *binaryWriter, name string) {
+		w.propertyType(s.PropertyTypes[name])
+	})
+
+	putUvarint(&index, uint64(len(s.Provenance)))
+	for _, name := range sortedKeys(s.Provenance) {
+		p := s.Provenance[name]
+		putString(&index, name)
+		putString(&index, string(p.Source))
+		putString(&index, p.Origin)
+	}
+
+	body := append(index.Bytes(), payloads.Bytes()...)
+
+	var out bytes.Buffer
+	out.WriteString(binaryMagic)
+	putUvarint(&out, BinaryFormatVersion)
+	putString(&out, s.ResourceSpecificationVersion)
+	out.Write(binary.LittleEndian.AppendUint32(nil, crc32.ChecksumIEEE(body)))
+	out.Write(body)
+	return out.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a spec encoded with MarshalBinary, replacing the
+// contents of s.
+func (s *Spec) UnmarshalBinary(data []byte) error {
+	lazy, err := OpenBinary(data)
+	if err != nil {
+		return err
+	}
+	decoded, err := lazy.Spec()
+	if err != nil {
+		return err
+	}
+	*s = *decoded
+	return nil
+}
+
+// BinaryVersion returns the ResourceSpecificationVersion recorded in the
+// header of a binary spec without decoding the rest.
+func BinaryVersion(data []byte) (string, error) {
+	r, err := readBinaryHeader(data)
+	if err != nil {
+		return "", err
+	}
+	return r.version, nil
+}
+
+// LazySpec is a binary-encoded spec whose types are decoded on first use.
+// It is safe for concurrent use.
+type LazySpec struct {
+	version       string
+	data          []byte
+	resourceTypes map[string]binarySpan
+	propertyTypes map[string]binarySpan
+	provenance    map[string]Provenance
+
+	mu                   sync.Mutex
+	decodedResourceTypes map[string]*ResourceType
+	decodedPropertyTypes map[string]*PropertyType
+	intern               map[string]string
+}
+
+// binarySpan locates a type payload in the encoded data.
+type binarySpan struct {
+	start, end int
+}
+
+// OpenBinary validates a binary spec and reads its index. Types are only
+// decoded when requested. The data must not be modified afterwards.
+func OpenBinary(data []byte) (*LazySpec, error) {
+	header, err := readBinaryHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	r := header.body
+	l := &LazySpec{
+		version:              header.version,
+		data:                 data,
+		decodedResourceTypes: make(map[string]*ResourceType),
+		decodedPropertyTypes: make(map[string]*PropertyType),
+		intern:               make(map[string]string),
+	}
+
+	readSection := func() map[string]binarySpan {
+		n := r.count()
+		section := make(map[string]binarySpan, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			name := r.string()
+			section[name] = binarySpan{end: int(r.uvarint())}
+		}
+		return section
+	}
+	l.resourceTypes = readSection()
+	l.propertyTypes = readSection()
+
+	if n := r.count(); n > 0 {
+		l.provenance = make(map[string]Provenance, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			name := r.string()
+			l.provenance[name] = Provenance{Source: Source(r.string()), Origin: r.string()}
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// Payloads follow the index in name order, resource types first.
+	offset := header.offset + r.off
+	for _, section := range []map[string]binarySpan{l.resourceTypes, l.propertyTypes} {
+		for _, name := range sortedKeys(section) {
+			span := section[name]
+			span.start, span.end = offset, offset+span.end
+			if span.end > len(data) {
+				return nil, fmt.Errorf("%w: truncated payload for %s", ErrBinaryFormat, name)
+			}
+			section[name] = span
+			offset = span.end
+		}
+	}
+	if offset != len(data) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrBinaryFormat, len(data)-offset)
+	}
+	return l, nil
+}
+
+// LoadBinary reads a binary spec file and opens it with OpenBinary.
+func LoadBinary(path string) (*LazySpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading spec file: %w", err)
+	}
+	return OpenBinary(data)
+}
+
+// Version returns the ResourceSpecificationVersion of the spec.
+func (l *LazySpec) Version() string {
+	return l.version
+}
+
+// ResourceTypeNames returns all resource type names, sorted.
+func (l *LazySpec) ResourceTypeNames() []string {
+	return sortedKeys(l.resourceTypes)
+}
+
+// PropertyTypeNames returns all property type names, sorted.
+func (l *LazySpec) PropertyTypeNames() []string {
+	return sortedKeys(l.propertyTypes)
+}
+
+// HasResourceType returns true if the spec contains the given resource type.
+func (l *LazySpec) HasResourceType(typeName string) bool {
+	_, ok := l.resourceTypes[typeName]
+	return ok
+}
+
+// HasPropertyType returns true if the spec contains the given property type.
+func (l *LazySpec) HasPropertyType(typeName string) bool {
+	_, ok := l.propertyTypes[typeName]
+	return ok
+}
+
+// GetResourceType decodes and returns a resource type. The result is
+// cached and shared between callers, so it must not be modified.
+// Returns nil if the resource type is not found.
+func (l *LazySpec) GetResourceType(typeName string) *ResourceType {
+	span, ok := l.resourceTypes[typeName]
+	if !ok {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rt, ok := l.decodedResourceTypes[typeName]; ok {
+		return rt
+	}
+	r := &binaryReader{data: l.data[span.start:span.end], intern: l.intern}
+	rt := r.resourceType()
+	l.decodedResourceTypes[typeName] = &rt
+	return &rt
+}
+
+// GetPropertyType decodes and returns a property type. The result is
+// cached and shared between callers, so it must not be modified.
+// Returns nil if the property type is not found.
+func (l *LazySpec) GetPropertyType(typeName string) *PropertyType {
+	span, ok := l.propertyTypes[typeName]
+	if !ok {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if pt, ok := l.decodedPropertyTypes[typeName]; ok {
+		return pt
+	}
+	r := &binaryReader{data: l.data[span.start:span.end], intern: l.intern}
+	pt := r.propertyType()
+	l.decodedPropertyTypes[typeName] = &pt
+	return &pt
+}
+
+// Spec decodes every type and returns the full spec.
+func (l *LazySpec) Spec() (*Spec, error) {
+	s := &Spec{
+		ResourceSpecificationVersion: l.version,
+		ResourceTypes:                make(map[string]ResourceType, len(l.resourceTypes)),
+		PropertyTypes:                make(map[string]PropertyType, len(l.propertyTypes)),
+	}
+	intern := make(map[string]string)
+	for name, span := range l.resourceTypes {
+		r := &binaryReader{data: l.data[span.start:span.end], intern: intern}
+		s.ResourceTypes[name] = r.resourceType()
+		if r.err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", name, r.err)
+		}
+	}
+	for name, span := range l.propertyTypes {
+		r := &binaryReader{data: l.data[span.start:span.end], intern: intern}
+		s.PropertyTypes[name] = r.propertyType()
+		if r.err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", name, r.err)
+		}
+	}
+	if l.provenance != nil {
+		s.Provenance = make(map[string]Provenance, len(l.provenance))
+		for name, p := range l.provenance {
+			s.Provenance[name] = p
+		}
+	}
+	return s, nil
+}
+
+// binaryHeader is the decoded header of a binary spec.
+type binaryHeader struct {
+	version string
+	offset  int // offset of the body in the data
+	body    *binaryReader
+}
+
+// readBinaryHeader checks the magic, format version and checksum.
+func readBinaryHeader(data []byte) (*binaryHeader, error) {
+	if !bytes.HasPrefix(data, []byte(binaryMagic)) {
+		return nil, fmt.Errorf("%w: missing header", ErrBinaryFormat)
+	}
+	r := &binaryReader{data: data, off: len(binaryMagic)}
+	if format := r.uvarint(); r.err == nil && format != BinaryFormatVersion {
+		return nil, fmt.Errorf("%w: format version %d, want %d", ErrBinaryFormat, format, BinaryFormatVersion)
+	}
+	version := r.string()
+	if r.err != nil || r.off+4 > len(data) {
+		return nil, fmt.Errorf("%w: truncated header", ErrBinaryFormat)
+	}
+	sum := binary.LittleEndian.Uint32(data[r.off:])
+	offset := r.off + 4
+	if crc32.ChecksumIEEE(data[offset:]) != sum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrBinaryFormat)
+	}
+	return &binaryHeader{
+		version: version,
+		offset:  offset,
+		body:    &binaryReader{data: data[offset:]},
+	}, nil
+}
+
+// binaryWriter encodes spec types. Maps are written in key order with
+// their length plus one, so that nil and empty maps round-trip.
+type binaryWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *binaryWriter) resourceType(rt ResourceType) {
+	putString(&w.buf, rt.Documentation)
+	putMapLen(&w.buf, len(rt.Attributes), rt.Attributes == nil)
+	for _, name := range sortedKeys(rt.Attributes) {
+		a := rt.Attributes[name]
+		putString(&w.buf, name)
+		putString(&w.buf, a.PrimitiveType)
+		putString(&w.buf, a.Type)
+		putString(&w.buf, a.PrimitiveItemType)
+		putString(&w.buf, a.ItemType)
+	}
+	w.properties(rt.Properties)
+	putBool(&w.buf, rt.AdditionalProperties)
+
+	putMapLen(&w.buf, len(rt.Handlers), rt.Handlers == nil)
+	ops := make([]string, 0, len(rt.Handlers))
+	for op := range rt.Handlers {
+		ops = append(ops, string(op))
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		h := rt.Handlers[Operation(op)]
+		putString(&w.buf, op)
+		putUvarint(&w.buf, uint64(len(h.Permissions)))
+		for _, p := range h.Permissions {
+			putString(&w.buf, p)
+		}
+		putUvarint(&w.buf, uint64(h.TimeoutInMinutes))
+	}
+}
+
+func (w *binaryWriter) propertyType(pt PropertyType) {
+	putString(&w.buf, pt.Documentation)
+	w.properties(pt.Properties)
+}
+
+func (w *binaryWriter) properties(props map[string]Property) {
+	putMapLen(&w.buf, len(props), props == nil)
+	for _, name := range sortedKeys(props) {
+		p := props[name]
+		putString(&w.buf, name)
+		putString(&w.buf, p.Documentation)
+		putBool(&w.buf, p.Required)
+		putString(&w.buf, p.PrimitiveType)
+		putString(&w.buf, p.Type)
+		putString(&w.buf, p.ItemType)
+		putString(&w.buf, p.PrimitiveItemType)
+		putString(&w.buf, p.UpdateType)
+		putBool(&w.buf, p.DuplicatesAllowed)
+	}
+}
+
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func putString(buf *bytes.Buffer, s string) {
+	putUvarint(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+func putBool(buf *bytes.Buffer, b bool) {
+	if b {
+		buf.WriteByte(1)
+	} else {
+		buf.WriteByte(0)
+	}
+}
+
+func putMapLen(buf *bytes.Buffer, n int, isNil bool) {
+	if isNil {
+		putUvarint(buf, 0)
+		return
+	}
+	putUvarint(buf, uint64(n)+1)
+}
+
+// binaryReader decodes spec types. The first error is kept in err and
+// later reads return zero values.
+type binaryReader struct {
+	data   []byte
+	off    int
+	err    error
+	intern map[string]string // shares short strings such as "String" and "Mutable"
+}
+
+// maxInternLength bounds the strings shared through binaryReader.intern.
+const maxInternLength = 32
+
+func (r *binaryReader) fail(msg string) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: %s at offset %d", ErrBinaryFormat, msg, r.off)
+	}
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data[r.off:])
+	if n <= 0 {
+		r.fail("invalid varint")
+		return 0
+	}
+	r.off += n
+	return v
+}
+
+// count reads a section length, bounded by the remaining data.
+func (r *binaryReader) count() int {
+	n := r.uvarint()
+	if n > uint64(len(r.data)-r.off) {
+		r.fail("invalid count")
+		return 0
+	}
+	return int(n)
+}
+
+func (r *binaryReader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+	b := r.data[r.off : r.off+n]
+	r.off += n
+	if r.intern == nil || n > maxInternLength {
+		return string(b)
+	}
+	if s, ok := r.intern[string(b)]; ok {
+		return s
+	}
+	s := string(b)
+	r.intern[s] = s
+	return s
+}
+
+func (r *binaryReader) bool() bool {
+	if r.err != nil {
+		return false
+	}
+	if r.off >= len(r.data) {
+		r.fail("truncated bool")
+		return false
+	}
+	b := r.data[r.off]
+	r.off++
+	return b != 0
+}
+
+// mapLen reads a map length written by putMapLen; ok is false for nil maps.
+func (r *binaryReader) mapLen() (n int, ok bool) {
+	v := r.uvarint()
+	if v == 0 {
+		return 0, false
+	}
+	if v-1 > uint64(len(r.data)-r.off) {
+		r.fail("invalid map length")
+		return 0, false
+	}
+	return int(v - 1), true
+}
+
+func (r *binaryReader) resourceType() ResourceType {
+	var rt ResourceType
+	rt.Documentation = r.string()
+	if n, ok := r.mapLen(); ok {
+		rt.Attributes = make(map[string]Attribute, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			name := r.string()
+			rt.Attributes[name] = Attribute{
+				PrimitiveType:     r.string(),
+				Type:              r.string(),
+				PrimitiveItemType: r.string(),
+				ItemType:          r.string(),
+			}
+		}
+	}
+	rt.Properties = r.properties()
+	rt.AdditionalProperties = r.bool()
+	if n, ok := r.mapLen(); ok {
+		rt.Handlers = make(map[Operation]Handler, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			op := Operation(r.string())
+			var h Handler
+			if count := r.count(); count > 0 {
+				h.Permissions = make([]string, count)
+				for j := range h.Permissions {
+					h.Permissions[j] = r.string()
+				}
+			}
+			h.TimeoutInMinutes = int(r.uvarint())
+			rt.Handlers[op] = h
+		}
+	}
+	return rt
+}
+
+func (r *binaryReader) propertyType() PropertyType {
+	var pt PropertyType
+	pt.Documentation = r.string()
+	pt.Properties = r.properties()
+	return pt
+}
+
+func (r *binaryReader) properties() map[string]Property {
+	n, ok := r.mapLen()
+	if !ok {
+		return nil
+	}
+	props := make(map[string]Property, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		name := r.string()
+		props[name] = Property{
+			Documentation:     r.string(),
+			Required:          r.bool(),
+			PrimitiveType:     r.string(),
+			Type:              r.string(),
+			ItemType:          r.string(),
+			PrimitiveItemType: r.string(),
+			UpdateType:        r.string(),
+			DuplicatesAllowed: r.bool(),
+		}
+	}
+	return props
+}
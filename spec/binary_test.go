@@ -0,0 +1,136 @@
+package spec_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+func TestSpec_BinaryRoundTrip(t *testing.T) {
+	s := loadSnapshot(t)
+	s.Provenance = map[string]spec.Provenance{"AWS::S3::Bucket": {Source: spec.SourceRegistry, Origin: "bucket.json"}}
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	var decoded spec.Spec
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if !reflect.DeepEqual(s, &decoded) {
+		t.Error("decoded spec differs from original")
+	}
+
+	again, _ := decoded.MarshalBinary()
+	if !bytes.Equal(data, again) {
+		t.Error("expected deterministic encoding")
+	}
+
+	version, err := spec.BinaryVersion(data)
+	if err != nil || version != s.ResourceSpecificationVersion {
+		t.Errorf("BinaryVersion = %q, %v, want %q", version, err, s.ResourceSpecificationVersion)
+	}
+}
+
+func TestOpenBinary_Lazy(t *testing.T) {
+	s := loadSnapshot(t)
+	data, _ := s.MarshalBinary()
+
+	lazy, err := spec.OpenBinary(data)
+	if err != nil {
+		t.Fatalf("OpenBinary failed: %v", err)
+	}
+	if lazy.Version() != s.ResourceSpecificationVersion {
+		t.Errorf("Version = %q", lazy.Version())
+	}
+	if got := lazy.ResourceTypeNames(); len(got) != len(s.ResourceTypes) || !sortedStrings(got) {
+		t.Errorf("unexpected resource type names %v", got)
+	}
+	if !lazy.HasResourceType("AWS::S3::Bucket") || lazy.HasResourceType("AWS::S3::Nope") {
+		t.Error("unexpected HasResourceType result")
+	}
+
+	rt := lazy.GetResourceType("AWS::S3::Bucket")
+	if rt == nil || !reflect.DeepEqual(*rt, s.ResourceTypes["AWS::S3::Bucket"]) {
+		t.Errorf("GetResourceType returned %+v", rt)
+	}
+	if lazy.GetResourceType("AWS::S3::Bucket") != rt {
+		t.Error("expected decoded resource type to be cached")
+	}
+	pt := lazy.GetPropertyType("Tag")
+	if pt == nil || !reflect.DeepEqual(*pt, s.PropertyTypes["Tag"]) {
+		t.Errorf("GetPropertyType returned %+v", pt)
+	}
+	if lazy.GetResourceType("AWS::S3::Nope") != nil || lazy.GetPropertyType("Nope") != nil {
+		t.Error("expected nil for unknown types")
+	}
+}
+
+func TestOpenBinary_Invalid(t *testing.T) {
+	data, _ := loadTestSpec(t).MarshalBinary()
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)-1] ^= 0xff
+
+	futureVersion := append([]byte(nil), data...)
+	futureVersion[len("CFNSPECB")] = spec.BinaryFormatVersion + 1
+
+	tests := map[string][]byte{
+		"empty":          nil,
+		"json":           []byte(testSpecJSON),
+		"truncated":      data[:len(data)/2],
+		"corrupt":        corrupt,
+		"format_version": futureVersion,
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := spec.OpenBinary(input); !errors.Is(err, spec.ErrBinaryFormat) {
+				t.Errorf("expected ErrBinaryFormat, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFetchSpec_BinaryCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testSpecJSON))
+	}))
+	cacheDir := t.TempDir()
+
+	opts := &spec.FetchOptions{URL: server.URL, CacheDir: cacheDir, Quiet: true, Binary: true}
+	fetched, err := spec.FetchSpec(opts)
+	if err != nil {
+		t.Fatalf("FetchSpec failed: %v", err)
+	}
+	server.Close()
+
+	lazy, err := spec.LoadBinary(filepath.Join(cacheDir, "spec-us-east-1-latest.bin"))
+	if err != nil {
+		t.Fatalf("expected binary cache file: %v", err)
+	}
+	if !lazy.HasResourceType("AWS::S3::Bucket") {
+		t.Error("expected cached spec to contain AWS::S3::Bucket")
+	}
+
+	cached, err := spec.FetchSpec(&spec.FetchOptions{URL: server.URL, CacheDir: cacheDir, Quiet: true, Binary: true})
+	if err != nil {
+		t.Fatalf("expected cached spec, got error: %v", err)
+	}
+	if !reflect.DeepEqual(fetched, cached) {
+		t.Error("expected cached spec to equal the downloaded spec")
+	}
+
+	// A JSON cache is not shared with the binary one.
+	if _, err := os.Stat(filepath.Join(cacheDir, "spec-us-east-1-latest.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no JSON cache file, got %v", err)
+	}
+}
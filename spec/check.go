@@ -0,0 +1,241 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity is the severity of a spec issue.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // the spec cannot be used reliably
+	SeverityWarning Severity = "warning" // the spec is usable but suspicious
+)
+
+// IssueCode identifies the kind of a spec issue.
+type IssueCode string
+
+const (
+	IssueUnknownType        IssueCode = "UnknownType"        // Type names an undefined property type
+	IssueUnknownItemType    IssueCode = "UnknownItemType"    // ItemType names an undefined property type
+	IssueConflictingTypes   IssueCode = "ConflictingTypes"   // both PrimitiveType and Type are set
+	IssueMissingType        IssueCode = "MissingType"        // neither PrimitiveType nor Type is set
+	IssueMissingItemType    IssueCode = "MissingItemType"    // a List or Map has no item type
+	IssueConflictingItems   IssueCode = "ConflictingItems"   // both PrimitiveItemType and ItemType are set
+	IssueUnexpectedItemType IssueCode = "UnexpectedItemType" // an item type on something that is not a List or Map
+	IssueInvalidPrimitive   IssueCode = "InvalidPrimitive"   // PrimitiveType or PrimitiveItemType is not a known primitive
+	IssueOrphanPropertyType IssueCode = "OrphanPropertyType" // no resource type uses the property type
+)
+
+// primitiveTypes are the valid values of PrimitiveType and PrimitiveItemType.
+var primitiveTypes = map[string]bool{
+	"Boolean":   true,
+	"Double":    true,
+	"Integer":   true,
+	"Json":      true,
+	"Long":      true,
+	"String":    true,
+	"Timestamp": true,
+}
+
+// Issue is a structural problem found by Check.
+type Issue struct {
+	Severity Severity
+	Code     IssueCode
+	TypeName string // resource or property type
+	Member   string // property or "Attributes.<name>"; empty for the type itself
+	Message  string
+}
+
+// String returns the issue as "error: AWS::S3::Bucket.Foo: UnknownType: ...".
+func (i Issue) String() string {
+	target := i.TypeName
+	if i.Member != "" {
+		target += "." + i.Member
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", i.Severity, target, i.Code, i.Message)
+}
+
+// CheckError reports a spec that failed Check with errors.
+type CheckError struct {
+	Issues []Issue // issues with SeverityError, sorted
+}
+
+// Error implements the error interface.
+func (e *CheckError) Error() string {
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		lines[i] = issue.String()
+	}
+	return fmt.Sprintf("spec has %d structural errors:\n%s", len(e.Issues), strings.Join(lines, "\n"))
+}
+
+// Check reports structural problems in the spec: properties and
+// attributes with missing, conflicting or undefined types, and property
+// types that no resource type uses. Issues are sorted by type, member and code.
+func Check(s *Spec) []Issue {
+	c := &checker{spec: s}
+
+	for name, rt := range s.ResourceTypes {
+		for propName, prop := range rt.Properties {
+			c.checkProperty(name, name, propName, prop)
+		}
+		for attrName, attr := range rt.Attributes {
+			c.checkAttribute(name, attrName, attr)
+		}
+	}
+	for name, pt := range s.PropertyTypes {
+		resourceType, _ := ParsePropertyTypeName(name)
+		for propName, prop := range pt.Properties {
+			c.checkProperty(name, resourceType, propName, prop)
+		}
+	}
+	c.checkOrphans()
+
+	sort.Slice(c.issues, func(i, j int) bool {
+		a, b := c.issues[i], c.issues[j]
+		if a.TypeName != b.TypeName {
+			return a.TypeName < b.TypeName
+		}
+		if a.Member != b.Member {
+			return a.Member < b.Member
+		}
+		return a.Code < b.Code
+	})
+	return c.issues
+}
+
+// Verify runs Check and returns a *CheckError if it finds any errors.
+// Warnings are ignored.
+func Verify(s *Spec) error {
+	var errs []Issue
+	for _, issue := range Check(s) {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	if len(errs) > 0 {
+		return &CheckError{Issues: errs}
+	}
+	return nil
+}
+
+type checker struct {
+	spec   *Spec
+	issues []Issue
+}
+
+func (c *checker) add(severity Severity, code IssueCode, typeName, member, format string, args ...any) {
+	c.issues = append(c.issues, Issue{
+		Severity: severity,
+		Code:     code,
+		TypeName: typeName,
+		Member:   member,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkProperty checks a property of owner. Property type names are
+// resolved relative to resourceType.
+func (c *checker) checkProperty(owner, resourceType, name string, prop Property) {
+	switch {
+	case prop.PrimitiveType != "" && prop.Type != "":
+		c.add(SeverityError, IssueConflictingTypes, owner, name, "both PrimitiveType %s and Type %s are set", prop.PrimitiveType, prop.Type)
+	case prop.PrimitiveType == "" && prop.Type == "":
+		c.add(SeverityError, IssueMissingType, owner, name, "neither PrimitiveType nor Type is set")
+	}
+	if prop.PrimitiveType != "" && !primitiveTypes[prop.PrimitiveType] {
+		c.add(SeverityError, IssueInvalidPrimitive, owner, name, "unknown primitive type %s", prop.PrimitiveType)
+	}
+	if prop.IsComplex() {
+		if _, pt := c.spec.ResolvePropertyType(resourceType, prop.Type); pt == nil {
+			c.add(SeverityError, IssueUnknownType, owner, name, "property type %s is not defined", prop.Type)
+		}
+	}
+	c.checkItems(owner, name, prop.Type, prop.PrimitiveItemType, prop.ItemType, func(itemType string) bool {
+		_, pt := c.spec.ResolvePropertyType(resourceType, itemType)
+		return pt != nil
+	})
+}
+
+// checkAttribute checks a resource attribute. Attributes are primitives,
+// or lists and maps of primitives or of property types of the resource.
+func (c *checker) checkAttribute(owner, name string, attr Attribute) {
+	member := "Attributes." + name
+	switch {
+	case attr.PrimitiveType != "" && attr.Type != "":
+		c.add(SeverityError, IssueConflictingTypes, owner, member, "both PrimitiveType %s and Type %s are set", attr.PrimitiveType, attr.Type)
+	case attr.PrimitiveType == "" && attr.Type == "":
+		c.add(SeverityError, IssueMissingType, owner, member, "neither PrimitiveType nor Type is set")
+	}
+	if attr.PrimitiveType != "" && !primitiveTypes[attr.PrimitiveType] {
+		c.add(SeverityError, IssueInvalidPrimitive, owner, member, "unknown primitive type %s", attr.PrimitiveType)
+	}
+	if attr.Type != "" && attr.Type != "List" && attr.Type != "Map" {
+		c.add(SeverityError, IssueUnknownType, owner, member, "attributes cannot have property type %s", attr.Type)
+	}
+	c.checkItems(owner, member, attr.Type, attr.PrimitiveItemType, attr.ItemType, func(itemType string) bool {
+		_, pt := c.spec.ResolvePropertyType(owner, itemType)
+		return pt != nil
+	})
+}
+
+// checkItems checks the item type of a List or Map. defined reports
+// whether a property type exists.
+func (c *checker) checkItems(owner, member, typ, primitiveItem, item string, defined func(string) bool) {
+	collection := typ == "List" || typ == "Map"
+	switch {
+	case !collection && (primitiveItem != "" || item != ""):
+		if typ != "" {
+			c.add(SeverityWarning, IssueUnexpectedItemType, owner, member, "item type set on non-collection type %s", typ)
+		}
+		return
+	case !collection:
+		return
+	case primitiveItem == "" && item == "":
+		c.add(SeverityError, IssueMissingItemType, owner, member, "%s has neither PrimitiveItemType nor ItemType", typ)
+	case primitiveItem != "" && item != "":
+		c.add(SeverityError, IssueConflictingItems, owner, member, "both PrimitiveItemType %s and ItemType %s are set", primitiveItem, item)
+	}
+	if primitiveItem != "" && !primitiveTypes[primitiveItem] {
+		c.add(SeverityError, IssueInvalidPrimitive, owner, member, "unknown primitive item type %s", primitiveItem)
+	}
+	if item != "" && !defined(item) {
+		c.add(SeverityError, IssueUnknownItemType, owner, member, "item type %s is not defined", item)
+	}
+}
+
+// checkOrphans reports property types not reachable from any resource type.
+func (c *checker) checkOrphans() {
+	used := make(map[string]bool)
+	var visit func(resourceType string, props map[string]Property)
+	visit = func(resourceType string, props map[string]Property) {
+		for _, prop := range props {
+			for _, typeName := range []string{prop.Type, prop.ItemType} {
+				if typeName == "" || typeName == "List" || typeName == "Map" {
+					continue
+				}
+				fullName, pt := c.spec.ResolvePropertyType(resourceType, typeName)
+				if pt == nil || used[fullName] {
+					continue
+				}
+				used[fullName] = true
+				visit(resourceType, pt.Properties)
+			}
+		}
+	}
+	for name, rt := range c.spec.ResourceTypes {
+		visit(name, rt.Properties)
+		for _, attr := range rt.Attributes {
+			visit(name, map[string]Property{"": {ItemType: attr.ItemType}})
+		}
+	}
+
+	for name := range c.spec.PropertyTypes {
+		if !used[name] {
+			c.add(SeverityWarning, IssueOrphanPropertyType, name, "", "no resource type uses this property type")
+		}
+	}
+}
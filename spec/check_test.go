@@ -0,0 +1,96 @@
+package spec_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+func TestCheck_Snapshot(t *testing.T) {
+	if issues := spec.Check(loadSnapshot(t)); len(issues) > 0 {
+		t.Errorf("expected no issues in the snapshot, got %v", issues)
+	}
+	if err := spec.Verify(loadSnapshot(t)); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s := &spec.Spec{
+		ResourceTypes: map[string]spec.ResourceType{
+			"AWS::Test::Thing": {
+				Properties: map[string]spec.Property{
+					"Good":        {PrimitiveType: "String"},
+					"Both":        {PrimitiveType: "String", Type: "Config"},
+					"Neither":     {},
+					"Missing":     {Type: "Nope"},
+					"BadItem":     {Type: "List", ItemType: "Nope"},
+					"NoItem":      {Type: "Map"},
+					"TwoItems":    {Type: "List", ItemType: "Config", PrimitiveItemType: "String"},
+					"BadPrim":     {PrimitiveType: "Str"},
+					"Config":      {Type: "Config"},
+					"StrayItem":   {Type: "Config", PrimitiveItemType: "String"},
+					"SharedTags":  {Type: "List", ItemType: "Tag"},
+					"GoodPrimMap": {Type: "Map", PrimitiveItemType: "Integer"},
+				},
+				Attributes: map[string]spec.Attribute{
+					"Arn":       {PrimitiveType: "String"},
+					"Ips":       {Type: "List"},
+					"Weird":     {Type: "Config"},
+					"Endpoints": {Type: "List", ItemType: "Endpoint"},
+					"Ghosts":    {Type: "List", ItemType: "Nope"},
+				},
+			},
+		},
+		PropertyTypes: map[string]spec.PropertyType{
+			"AWS::Test::Thing.Config": {Properties: map[string]spec.Property{
+				"Nested": {Type: "Inner"},
+			}},
+			"AWS::Test::Thing.Inner":    {Properties: map[string]spec.Property{"Value": {PrimitiveType: "String"}}},
+			"AWS::Test::Thing.Endpoint": {Properties: map[string]spec.Property{"Address": {PrimitiveType: "String"}}},
+			"AWS::Test::Thing.Unused":   {Properties: map[string]spec.Property{"Value": {PrimitiveType: "String"}}},
+			"Tag":                       {Properties: map[string]spec.Property{"Key": {PrimitiveType: "String"}}},
+		},
+	}
+
+	var got []string
+	for _, issue := range spec.Check(s) {
+		got = append(got, string(issue.Severity)+" "+issue.TypeName+" "+issue.Member+" "+string(issue.Code))
+	}
+	want := []string{
+		"error AWS::Test::Thing Attributes.Ghosts UnknownItemType",
+		"error AWS::Test::Thing Attributes.Ips MissingItemType",
+		"error AWS::Test::Thing Attributes.Weird UnknownType",
+		"error AWS::Test::Thing BadItem UnknownItemType",
+		"error AWS::Test::Thing BadPrim InvalidPrimitive",
+		"error AWS::Test::Thing Both ConflictingTypes",
+		"error AWS::Test::Thing Missing UnknownType",
+		"error AWS::Test::Thing Neither MissingType",
+		"error AWS::Test::Thing NoItem MissingItemType",
+		"warning AWS::Test::Thing StrayItem UnexpectedItemType",
+		"error AWS::Test::Thing TwoItems ConflictingItems",
+		"warning AWS::Test::Thing.Unused  OrphanPropertyType",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	err := spec.Verify(s)
+	var checkErr *spec.CheckError
+	if !errors.As(err, &checkErr) {
+		t.Fatalf("expected *CheckError, got %v", err)
+	}
+	if len(checkErr.Issues) != 10 {
+		t.Errorf("expected 10 errors, got %d", len(checkErr.Issues))
+	}
+	for _, issue := range checkErr.Issues {
+		if issue.Severity != spec.SeverityError {
+			t.Errorf("expected only errors in CheckError, got %s", issue)
+		}
+	}
+	if !strings.Contains(err.Error(), "error: AWS::Test::Thing.Missing: UnknownType: property type Nope is not defined") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
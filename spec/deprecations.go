@@ -0,0 +1,244 @@
+package spec
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DeprecationStatus is the lifecycle status of a deprecated type, property or value.
+type DeprecationStatus string
+
+const (
+	StatusDeprecated DeprecationStatus = "Deprecated" // scheduled for or past removal
+	StatusLegacy     DeprecationStatus = "Legacy"     // still supported, but superseded
+)
+
+// DeprecationKind is what a deprecation applies to.
+type DeprecationKind string
+
+const (
+	DeprecatedResourceType DeprecationKind = "ResourceType"
+	DeprecatedProperty     DeprecationKind = "Property"
+	DeprecatedValue        DeprecationKind = "Value"
+)
+
+// Deprecation describes a deprecated resource type, property or property value.
+type Deprecation struct {
+	Kind         DeprecationKind
+	ResourceType string // resource or property type name
+	Property     string // empty for resource types
+	Value        string // empty unless Kind is DeprecatedValue
+	Status       DeprecationStatus
+	Replacement  string    // suggested type, property or value to use instead
+	Sunset       time.Time // zero if no date is announced
+	Message      string    // optional background
+}
+
+// Target returns what the deprecation applies to, e.g. "AWS::SDB::Domain",
+// "AWS::S3::Bucket.AccessControl" or "AWS::Lambda::Function.Runtime=nodejs12.x".
+func (d *Deprecation) Target() string {
+	target := d.ResourceType
+	if d.Property != "" {
+		target += "." + d.Property
+	}
+	if d.Kind == DeprecatedValue {
+		target += "=" + d.Value
+	}
+	return target
+}
+
+// IsSunset returns true if the sunset date is set and not after t.
+func (d *Deprecation) IsSunset(t time.Time) bool {
+	return !d.Sunset.IsZero() && !t.Before(d.Sunset)
+}
+
+// String returns a one-line warning, e.g. "AWS::Lambda::Function.Runtime=nodejs12.x
+// is deprecated (sunset 2023-03-31); use nodejs22.x instead".
+func (d *Deprecation) String() string {
+	var b strings.Builder
+	b.WriteString(d.Target())
+	b.WriteString(" is ")
+	b.WriteString(strings.ToLower(string(d.Status)))
+	if !d.Sunset.IsZero() {
+		b.WriteString(" (sunset ")
+		b.WriteString(d.Sunset.Format(time.DateOnly))
+		b.WriteString(")")
+	}
+	if d.Replacement != "" {
+		b.WriteString("; use ")
+		b.WriteString(d.Replacement)
+		b.WriteString(" instead")
+	}
+	if d.Message != "" {
+		b.WriteString(". ")
+		b.WriteString(d.Message)
+	}
+	return b.String()
+}
+
+//go:embed deprecations/deprecations.json
+var deprecationsJSON []byte
+
+// deprecationEntry is a deprecation in the bundled registry.
+type deprecationEntry struct {
+	Status      DeprecationStatus
+	Replacement string
+	Sunset      string // YYYY-MM-DD
+	Message     string
+}
+
+// deprecationTable is the bundled deprecation registry.
+type deprecationTable struct {
+	ResourceTypes map[string]*Deprecation            // resource type
+	Properties    map[string]*Deprecation            // "Type.Property"
+	Values        map[string]map[string]*Deprecation // "Type.Property" -> value
+}
+
+var deprecations = func() deprecationTable {
+	var raw struct {
+		ResourceTypes map[string]deprecationEntry
+		Properties    map[string]deprecationEntry
+		Values        map[string]map[string]deprecationEntry
+	}
+	if err := json.Unmarshal(deprecationsJSON, &raw); err != nil {
+		panic(fmt.Sprintf("spec: invalid bundled deprecations: %v", err))
+	}
+
+	table := deprecationTable{
+		ResourceTypes: make(map[string]*Deprecation),
+		Properties:    make(map[string]*Deprecation),
+		Values:        make(map[string]map[string]*Deprecation),
+	}
+	for typeName, e := range raw.ResourceTypes {
+		table.ResourceTypes[typeName] = e.deprecation(DeprecatedResourceType, typeName, "", "")
+	}
+	for key, e := range raw.Properties {
+		typeName, prop := splitPropertyKey(key)
+		table.Properties[key] = e.deprecation(DeprecatedProperty, typeName, prop, "")
+	}
+	for key, values := range raw.Values {
+		typeName, prop := splitPropertyKey(key)
+		table.Values[key] = make(map[string]*Deprecation, len(values))
+		for value, e := range values {
+			table.Values[key][value] = e.deprecation(DeprecatedValue, typeName, prop, value)
+		}
+	}
+	return table
+}()
+
+func (e deprecationEntry) deprecation(kind DeprecationKind, typeName, prop, value string) *Deprecation {
+	d := &Deprecation{
+		Kind:         kind,
+		ResourceType: typeName,
+		Property:     prop,
+		Value:        value,
+		Status:       e.Status,
+		Replacement:  e.Replacement,
+		Message:      e.Message,
+	}
+	if e.Status != StatusDeprecated && e.Status != StatusLegacy {
+		panic(fmt.Sprintf("spec: invalid bundled deprecation status %q for %s", e.Status, d.Target()))
+	}
+	if e.Sunset != "" {
+		sunset, err := time.Parse(time.DateOnly, e.Sunset)
+		if err != nil {
+			panic(fmt.Sprintf("spec: invalid bundled sunset date for %s: %v", d.Target(), err))
+		}
+		d.Sunset = sunset
+	}
+	return d
+}
+
+// splitPropertyKey splits "Type.Property" at its last dot, so property type
+// names such as "AWS::S3::Bucket.Rule.Status" work too.
+func splitPropertyKey(key string) (typeName, prop string) {
+	i := strings.LastIndex(key, ".")
+	if i < 0 {
+		return key, ""
+	}
+	return key[:i], key[i+1:]
+}
+
+// ResourceTypeDeprecation returns the deprecation of a resource type from
+// the bundled registry, or nil if it is not deprecated.
+func ResourceTypeDeprecation(resourceType string) *Deprecation {
+	return deprecations.ResourceTypes[resourceType]
+}
+
+// PropertyDeprecation returns the deprecation of a property of a resource
+// or property type, or nil if it is not deprecated.
+func PropertyDeprecation(typeName, property string) *Deprecation {
+	return deprecations.Properties[typeName+"."+property]
+}
+
+// ValueDeprecation returns the deprecation of a property value, such as
+// a Lambda runtime, or nil if the value is not deprecated.
+func ValueDeprecation(typeName, property, value string) *Deprecation {
+	return deprecations.Values[typeName+"."+property][value]
+}
+
+// DeprecatedValues returns the deprecated values of a property, sorted.
+func DeprecatedValues(typeName, property string) []string {
+	values := deprecations.Values[typeName+"."+property]
+	result := make([]string, 0, len(values))
+	for value := range values {
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Deprecations returns every entry of the bundled registry, sorted by target.
+func Deprecations() []*Deprecation {
+	var result []*Deprecation
+	for _, d := range deprecations.ResourceTypes {
+		result = append(result, d)
+	}
+	for _, d := range deprecations.Properties {
+		result = append(result, d)
+	}
+	for _, values := range deprecations.Values {
+		for _, d := range values {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Target() < result[j].Target()
+	})
+	return result
+}
+
+// Deprecations returns the registry entries that apply to types and
+// properties defined in the spec, sorted by target.
+func (s *Spec) Deprecations() []*Deprecation {
+	var result []*Deprecation
+	for _, d := range Deprecations() {
+		if d.Kind == DeprecatedResourceType {
+			if s.HasResourceType(d.ResourceType) {
+				result = append(result, d)
+			}
+			continue
+		}
+		if s.hasProperty(d.ResourceType, d.Property) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// hasProperty returns true if the resource or property type has the property.
+func (s *Spec) hasProperty(typeName, property string) bool {
+	if rt, ok := s.ResourceTypes[typeName]; ok {
+		_, ok := rt.Properties[property]
+		return ok
+	}
+	if pt, ok := s.PropertyTypes[typeName]; ok {
+		_, ok := pt.Properties[property]
+		return ok
+	}
+	return false
+}
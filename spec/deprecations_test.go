@@ -0,0 +1,102 @@
+package spec_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+func TestResourceTypeDeprecation(t *testing.T) {
+	d := spec.ResourceTypeDeprecation("AWS::SDB::Domain")
+	if d == nil {
+		t.Fatal("expected AWS::SDB::Domain to be deprecated")
+	}
+	if d.Kind != spec.DeprecatedResourceType || d.Status != spec.StatusLegacy {
+		t.Errorf("got %s %s, want ResourceType Legacy", d.Kind, d.Status)
+	}
+	if d.Replacement != "AWS::DynamoDB::Table" {
+		t.Errorf("Replacement = %q", d.Replacement)
+	}
+
+	opsworks := spec.ResourceTypeDeprecation("AWS::OpsWorks::Stack")
+	if opsworks == nil || opsworks.Sunset.IsZero() {
+		t.Fatalf("expected AWS::OpsWorks::Stack to have a sunset date, got %v", opsworks)
+	}
+
+	if got := spec.ResourceTypeDeprecation("AWS::S3::Bucket"); got != nil {
+		t.Errorf("expected AWS::S3::Bucket not to be deprecated, got %s", got)
+	}
+}
+
+func TestPropertyDeprecation(t *testing.T) {
+	d := spec.PropertyDeprecation("AWS::S3::Bucket", "AccessControl")
+	if d == nil {
+		t.Fatal("expected AWS::S3::Bucket.AccessControl to be deprecated")
+	}
+	if d.Target() != "AWS::S3::Bucket.AccessControl" || d.Property != "AccessControl" {
+		t.Errorf("got target %s, property %s", d.Target(), d.Property)
+	}
+	if got := spec.PropertyDeprecation("AWS::S3::Bucket", "BucketName"); got != nil {
+		t.Errorf("expected BucketName not to be deprecated, got %s", got)
+	}
+}
+
+func TestValueDeprecation(t *testing.T) {
+	d := spec.ValueDeprecation("AWS::Lambda::Function", "Runtime", "nodejs12.x")
+	if d == nil {
+		t.Fatal("expected nodejs12.x to be deprecated")
+	}
+	want := "AWS::Lambda::Function.Runtime=nodejs12.x is deprecated (sunset 2023-03-31); use nodejs22.x instead"
+	if got := d.String(); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if !d.IsSunset(time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected nodejs12.x to be sunset on its sunset date")
+	}
+	if d.IsSunset(time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected nodejs12.x not to be sunset before its sunset date")
+	}
+
+	if got := spec.ValueDeprecation("AWS::Lambda::Function", "Runtime", "python3.12"); got != nil {
+		t.Errorf("expected python3.12 not to be deprecated, got %s", got)
+	}
+
+	values := spec.DeprecatedValues("AWS::Lambda::Function", "Runtime")
+	if !contains(values, "python2.7") || !contains(values, "go1.x") {
+		t.Errorf("DeprecatedValues = %v", values)
+	}
+	if !sort.StringsAreSorted(values) {
+		t.Errorf("expected sorted values, got %v", values)
+	}
+}
+
+func TestDeprecations(t *testing.T) {
+	all := spec.Deprecations()
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Target() >= all[i].Target() {
+			t.Errorf("not sorted: %s before %s", all[i-1].Target(), all[i].Target())
+		}
+	}
+	for _, d := range all {
+		if d.Replacement == "" {
+			t.Errorf("%s has no replacement", d.Target())
+		}
+	}
+
+	// The snapshot includes AWS::SDB::Domain, AWS::S3::Bucket and Lambda
+	// functions but no OpsWorks types.
+	var targets []string
+	for _, d := range loadSnapshot(t).Deprecations() {
+		targets = append(targets, d.Target())
+	}
+	for _, want := range []string{"AWS::SDB::Domain", "AWS::S3::Bucket.AccessControl", "AWS::Lambda::Function.Runtime=nodejs12.x"} {
+		if !contains(targets, want) {
+			t.Errorf("expected %s in spec deprecations", want)
+		}
+	}
+	if contains(targets, "AWS::OpsWorks::Stack") {
+		t.Error("expected types missing from the spec to be skipped")
+	}
+}
@@ -0,0 +1,260 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind describes how an element changed between two specs.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "Added"
+	ChangeRemoved  ChangeKind = "Removed"
+	ChangeModified ChangeKind = "Modified"
+)
+
+// ChangeTarget is the kind of spec element a change applies to.
+type ChangeTarget string
+
+const (
+	TargetResourceType ChangeTarget = "ResourceType"
+	TargetPropertyType ChangeTarget = "PropertyType"
+	TargetProperty     ChangeTarget = "Property"
+	TargetAttribute    ChangeTarget = "Attribute"
+)
+
+// targetOrder is the order targets appear in a changelog.
+var targetOrder = []ChangeTarget{TargetResourceType, TargetPropertyType, TargetProperty, TargetAttribute}
+
+// Change is a single difference between two specs.
+//
+// For type-level changes TypeName is the resource or property type and Name
+// is empty. For property and attribute changes TypeName is the owning type.
+// Modified changes name the Field that changed with its Old and New values.
+type Change struct {
+	Kind     ChangeKind   `json:"Kind"`
+	Target   ChangeTarget `json:"Target"`
+	TypeName string       `json:"TypeName"`
+	Name     string       `json:"Name,omitempty"`
+	Field    string       `json:"Field,omitempty"`
+	Old      string       `json:"Old,omitempty"`
+	New      string       `json:"New,omitempty"`
+}
+
+// Path returns the dotted path of the changed element, e.g. "AWS::S3::Bucket.BucketName".
+func (c Change) Path() string {
+	if c.Name == "" {
+		return c.TypeName
+	}
+	return c.TypeName + "." + c.Name
+}
+
+// SpecDiff is the set of changes between two specs.
+type SpecDiff struct {
+	OldVersion string   `json:"OldVersion"`
+	NewVersion string   `json:"NewVersion"`
+	Changes    []Change `json:"Changes"`
+}
+
+// Diff reports the differences between oldSpec and newSpec.
+// Changes are sorted by type name, target, element name and field.
+// Properties and attributes of added or removed types are not listed individually.
+func Diff(oldSpec, newSpec *Spec) *SpecDiff {
+	d := &SpecDiff{
+		OldVersion: oldSpec.ResourceSpecificationVersion,
+		NewVersion: newSpec.ResourceSpecificationVersion,
+	}
+
+	for _, name := range sortedKeys(oldSpec.ResourceTypes, newSpec.ResourceTypes) {
+		oldRT, inOld := oldSpec.ResourceTypes[name]
+		newRT, inNew := newSpec.ResourceTypes[name]
+		switch {
+		case !inOld:
+			d.add(Change{Kind: ChangeAdded, Target: TargetResourceType, TypeName: name})
+		case !inNew:
+			d.add(Change{Kind: ChangeRemoved, Target: TargetResourceType, TypeName: name})
+		default:
+			d.diffProperties(name, oldRT.Properties, newRT.Properties)
+			d.diffAttributes(name, oldRT.Attributes, newRT.Attributes)
+		}
+	}
+
+	for _, name := range sortedKeys(oldSpec.PropertyTypes, newSpec.PropertyTypes) {
+		oldPT, inOld := oldSpec.PropertyTypes[name]
+		newPT, inNew := newSpec.PropertyTypes[name]
+		switch {
+		case !inOld:
+			d.add(Change{Kind: ChangeAdded, Target: TargetPropertyType, TypeName: name})
+		case !inNew:
+			d.add(Change{Kind: ChangeRemoved, Target: TargetPropertyType, TypeName: name})
+		default:
+			d.diffProperties(name, oldPT.Properties, newPT.Properties)
+		}
+	}
+
+	sort.SliceStable(d.Changes, func(i, j int) bool {
+		a, b := d.Changes[i], d.Changes[j]
+		if a.TypeName != b.TypeName {
+			return a.TypeName < b.TypeName
+		}
+		if a.Target != b.Target {
+			return targetIndex(a.Target) < targetIndex(b.Target)
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Field < b.Field
+	})
+
+	return d
+}
+
+// IsEmpty returns true if the specs have no differences.
+func (d *SpecDiff) IsEmpty() bool {
+	return len(d.Changes) == 0
+}
+
+// JSON renders the diff as indented JSON.
+func (d *SpecDiff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Markdown renders the diff as a markdown changelog grouped by target.
+func (d *SpecDiff) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# CloudFormation spec changes: %s → %s\n", d.OldVersion, d.NewVersion)
+
+	if d.IsEmpty() {
+		b.WriteString("\nNo changes.\n")
+		return b.String()
+	}
+
+	headings := map[ChangeTarget]string{
+		TargetResourceType: "Resource types",
+		TargetPropertyType: "Property types",
+		TargetProperty:     "Properties",
+		TargetAttribute:    "Attributes",
+	}
+	for _, target := range targetOrder {
+		var lines []string
+		for _, c := range d.Changes {
+			if c.Target != target {
+				continue
+			}
+			switch c.Kind {
+			case ChangeModified:
+				lines = append(lines, fmt.Sprintf("- Modified `%s` %s: %s → %s", c.Path(), c.Field, markdownValue(c.Old), markdownValue(c.New)))
+			default:
+				lines = append(lines, fmt.Sprintf("- %s `%s`", c.Kind, c.Path()))
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", headings[target])
+		b.WriteString(strings.Join(lines, "\n"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// markdownValue formats a field value for markdown, marking empty values.
+func markdownValue(v string) string {
+	if v == "" {
+		return "_none_"
+	}
+	return "`" + v + "`"
+}
+
+func (d *SpecDiff) add(c Change) {
+	d.Changes = append(d.Changes, c)
+}
+
+func (d *SpecDiff) diffProperties(typeName string, oldProps, newProps map[string]Property) {
+	for _, name := range sortedKeys(oldProps, newProps) {
+		oldProp, inOld := oldProps[name]
+		newProp, inNew := newProps[name]
+		switch {
+		case !inOld:
+			d.add(Change{Kind: ChangeAdded, Target: TargetProperty, TypeName: typeName, Name: name})
+		case !inNew:
+			d.add(Change{Kind: ChangeRemoved, Target: TargetProperty, TypeName: typeName, Name: name})
+		default:
+			fields := []struct {
+				field    string
+				old, new string
+			}{
+				{"Required", strconv.FormatBool(oldProp.Required), strconv.FormatBool(newProp.Required)},
+				{"PrimitiveType", oldProp.PrimitiveType, newProp.PrimitiveType},
+				{"Type", oldProp.Type, newProp.Type},
+				{"ItemType", oldProp.ItemType, newProp.ItemType},
+				{"PrimitiveItemType", oldProp.PrimitiveItemType, newProp.PrimitiveItemType},
+				{"UpdateType", oldProp.UpdateType, newProp.UpdateType},
+				{"DuplicatesAllowed", strconv.FormatBool(oldProp.DuplicatesAllowed), strconv.FormatBool(newProp.DuplicatesAllowed)},
+			}
+			for _, f := range fields {
+				if f.old != f.new {
+					d.add(Change{Kind: ChangeModified, Target: TargetProperty, TypeName: typeName, Name: name, Field: f.field, Old: f.old, New: f.new})
+				}
+			}
+		}
+	}
+}
+
+func (d *SpecDiff) diffAttributes(typeName string, oldAttrs, newAttrs map[string]Attribute) {
+	for _, name := range sortedKeys(oldAttrs, newAttrs) {
+		oldAttr, inOld := oldAttrs[name]
+		newAttr, inNew := newAttrs[name]
+		switch {
+		case !inOld:
+			d.add(Change{Kind: ChangeAdded, Target: TargetAttribute, TypeName: typeName, Name: name})
+		case !inNew:
+			d.add(Change{Kind: ChangeRemoved, Target: TargetAttribute, TypeName: typeName, Name: name})
+		default:
+			fields := []struct {
+				field    string
+				old, new string
+			}{
+				{"PrimitiveType", oldAttr.PrimitiveType, newAttr.PrimitiveType},
+				{"Type", oldAttr.Type, newAttr.Type},
+				{"ItemType", oldAttr.ItemType, newAttr.ItemType},
+				{"PrimitiveItemType", oldAttr.PrimitiveItemType, newAttr.PrimitiveItemType},
+			}
+			for _, f := range fields {
+				if f.old != f.new {
+					d.add(Change{Kind: ChangeModified, Target: TargetAttribute, TypeName: typeName, Name: name, Field: f.field, Old: f.old, New: f.new})
+				}
+			}
+		}
+	}
+}
+
+func targetIndex(t ChangeTarget) int {
+	for i, target := range targetOrder {
+		if target == t {
+			return i
+		}
+	}
+	return len(targetOrder)
+}
+
+// sortedKeys returns the sorted union of the keys of maps.
+func sortedKeys[V any](maps ...map[string]V) []string {
+	seen := make(map[string]bool)
+	for _, m := range maps {
+		for k := range m {
+			seen[k] = true
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
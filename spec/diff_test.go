@@ -0,0 +1,102 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+func TestDiff_NoChanges(t *testing.T) {
+	d := spec.Diff(loadTestSpec(t), loadTestSpec(t))
+	if !d.IsEmpty() {
+		t.Errorf("expected no changes, got %+v", d.Changes)
+	}
+	if !strings.Contains(d.Markdown(), "No changes.") {
+		t.Errorf("expected markdown to report no changes, got %q", d.Markdown())
+	}
+}
+
+func TestDiff(t *testing.T) {
+	oldSpec := loadTestSpec(t)
+	newSpec := loadTestSpec(t)
+	newSpec.ResourceSpecificationVersion = "2.0.0"
+
+	// Remove a resource type and add another.
+	delete(newSpec.ResourceTypes, "AWS::EC2::Instance")
+	newSpec.ResourceTypes["AWS::SQS::Queue"] = spec.ResourceType{}
+
+	// Modify, add and remove bucket properties and attributes.
+	bucket := newSpec.ResourceTypes["AWS::S3::Bucket"]
+	bucket.Properties = map[string]spec.Property{
+		"BucketName": {Required: true, PrimitiveType: "String", UpdateType: "Immutable"},
+		"Tags":       {Type: "List", ItemType: "Tag", UpdateType: "Mutable", DuplicatesAllowed: true},
+		"ObjectLock": {PrimitiveType: "Boolean"},
+	}
+	bucket.Attributes = map[string]spec.Attribute{
+		"Arn":        {PrimitiveType: "String"},
+		"DomainName": {Type: "List", PrimitiveItemType: "String"},
+	}
+	newSpec.ResourceTypes["AWS::S3::Bucket"] = bucket
+
+	// Remove a property type.
+	delete(newSpec.PropertyTypes, "AWS::S3::Bucket.CorsConfiguration")
+
+	d := spec.Diff(oldSpec, newSpec)
+
+	want := []spec.Change{
+		{Kind: spec.ChangeRemoved, Target: spec.TargetResourceType, TypeName: "AWS::EC2::Instance"},
+		{Kind: spec.ChangeModified, Target: spec.TargetProperty, TypeName: "AWS::S3::Bucket", Name: "BucketName", Field: "Required", Old: "false", New: "true"},
+		{Kind: spec.ChangeRemoved, Target: spec.TargetProperty, TypeName: "AWS::S3::Bucket", Name: "CorsConfiguration"},
+		{Kind: spec.ChangeAdded, Target: spec.TargetProperty, TypeName: "AWS::S3::Bucket", Name: "ObjectLock"},
+		{Kind: spec.ChangeModified, Target: spec.TargetProperty, TypeName: "AWS::S3::Bucket", Name: "Tags", Field: "DuplicatesAllowed", Old: "false", New: "true"},
+		{Kind: spec.ChangeModified, Target: spec.TargetAttribute, TypeName: "AWS::S3::Bucket", Name: "DomainName", Field: "PrimitiveItemType", Old: "", New: "String"},
+		{Kind: spec.ChangeModified, Target: spec.TargetAttribute, TypeName: "AWS::S3::Bucket", Name: "DomainName", Field: "PrimitiveType", Old: "String", New: ""},
+		{Kind: spec.ChangeModified, Target: spec.TargetAttribute, TypeName: "AWS::S3::Bucket", Name: "DomainName", Field: "Type", Old: "", New: "List"},
+		{Kind: spec.ChangeRemoved, Target: spec.TargetPropertyType, TypeName: "AWS::S3::Bucket.CorsConfiguration"},
+		{Kind: spec.ChangeAdded, Target: spec.TargetResourceType, TypeName: "AWS::SQS::Queue"},
+	}
+	if !reflect.DeepEqual(d.Changes, want) {
+		t.Errorf("Diff() changes:\n got %+v\nwant %+v", d.Changes, want)
+	}
+}
+
+func TestSpecDiff_Renderers(t *testing.T) {
+	oldSpec := loadTestSpec(t)
+	newSpec := loadTestSpec(t)
+	newSpec.ResourceSpecificationVersion = "2.0.0"
+	newSpec.ResourceTypes["AWS::SQS::Queue"] = spec.ResourceType{}
+	instance := newSpec.ResourceTypes["AWS::EC2::Instance"]
+	instance.Properties = map[string]spec.Property{
+		"InstanceType": {Required: true, PrimitiveType: "String", UpdateType: "Conditional"},
+		"ImageId":      {Required: true, PrimitiveType: "String"},
+	}
+	newSpec.ResourceTypes["AWS::EC2::Instance"] = instance
+
+	d := spec.Diff(oldSpec, newSpec)
+
+	md := d.Markdown()
+	for _, want := range []string{
+		"# CloudFormation spec changes: 1.0.0 → 2.0.0",
+		"## Resource types\n\n- Added `AWS::SQS::Queue`",
+		"## Properties\n\n- Modified `AWS::EC2::Instance.InstanceType` UpdateType: _none_ → `Conditional`",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("expected markdown to contain %q, got:\n%s", want, md)
+		}
+	}
+
+	data, err := d.JSON()
+	if err != nil {
+		t.Fatalf("JSON failed: %v", err)
+	}
+	var decoded spec.SpecDiff
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal JSON changelog: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, d) {
+		t.Errorf("JSON round trip mismatch:\n got %+v\nwant %+v", decoded, d)
+	}
+}
@@ -8,16 +8,75 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 // DefaultSpecURL is the URL for the CloudFormation Resource Specification.
 const DefaultSpecURL = "https://d1uauaxba7bl26.cloudfront.net/latest/gzip/CloudFormationResourceSpecification.json"
 
+// DefaultRegion is the region whose spec is fetched when no region is given.
+const DefaultRegion = "us-east-1"
+
+// LatestVersion selects the most recent published spec.
+const LatestVersion = "latest"
+
+// specFile is the path of the spec below a region's base URL and version.
+const specFile = "gzip/CloudFormationResourceSpecification.json"
+
+// regionBaseURLs maps regions to the base URL their spec is published under.
+// Regions not listed here use the regional S3 bucket (see SpecURL).
+var regionBaseURLs = map[string]string{
+	"ap-northeast-1": "https://d33vqc0rt9ld30.cloudfront.net/",
+	"ap-northeast-2": "https://d1ane3fvebulky.cloudfront.net/",
+	"ap-northeast-3": "https://d2zq80gdmjim8k.cloudfront.net/",
+	"ap-south-1":     "https://d2senuesg1djtx.cloudfront.net/",
+	"ap-southeast-1": "https://doigdx0kgq9el.cloudfront.net/",
+	"ap-southeast-2": "https://d2stg8d246z9di.cloudfront.net/",
+	"ca-central-1":   "https://d2s8ygphhesbe7.cloudfront.net/",
+	"eu-central-1":   "https://d1mta8qj7i28i2.cloudfront.net/",
+	"eu-north-1":     "https://diy8iv58sj6ba.cloudfront.net/",
+	"eu-west-1":      "https://d3teyb21fexa9r.cloudfront.net/",
+	"eu-west-2":      "https://d1742qcu2c1ncx.cloudfront.net/",
+	"eu-west-3":      "https://d2d0mfegowb3wk.cloudfront.net/",
+	"sa-east-1":      "https://d3c9jyj3w509b0.cloudfront.net/",
+	"us-east-1":      "https://d1uauaxba7bl26.cloudfront.net/",
+	"us-east-2":      "https://dnwj8swjjbsbt.cloudfront.net/",
+	"us-gov-east-1":  "https://s3.us-gov-east-1.amazonaws.com/cfn-resource-specifications-us-gov-east-1-prod/",
+	"us-gov-west-1":  "https://s3.us-gov-west-1.amazonaws.com/cfn-resource-specifications-us-gov-west-1-prod/",
+	"us-west-1":      "https://d68hl49wbnanq.cloudfront.net/",
+	"us-west-2":      "https://d201a2mn26r7lk.cloudfront.net/",
+}
+
+// SpecURL returns the URL of the spec published for region at version.
+// An empty region means DefaultRegion and an empty version means LatestVersion.
+func SpecURL(region, version string) string {
+	if region == "" {
+		region = DefaultRegion
+	}
+	if version == "" {
+		version = LatestVersion
+	}
+	base, ok := regionBaseURLs[region]
+	if !ok {
+		suffix := "amazonaws.com"
+		if strings.HasPrefix(region, "cn-") {
+			suffix = "amazonaws.com.cn"
+		}
+		base = fmt.Sprintf("https://cfn-resource-specifications-%s-prod.s3.%s.%s/", region, region, suffix)
+	}
+	return base + version + "/" + specFile
+}
+
 // FetchOptions configures how the spec is fetched.
 type FetchOptions struct {
-	// URL to fetch the spec from. Defaults to DefaultSpecURL.
+	// URL to fetch the spec from. Defaults to SpecURL(Region, Version).
 	URL string
+	// Region selects the regional spec. Defaults to DefaultRegion.
+	Region string
+	// Version selects a published spec version, e.g. "150.0.0".
+	// Defaults to LatestVersion.
+	Version string
 	// Force re-download even if cached.
 	Force bool
 	// CacheDir is the directory to cache the spec. Defaults to system temp dir.
@@ -27,6 +86,10 @@ type FetchOptions struct {
 	MaxAge time.Duration
 	// Quiet suppresses progress output.
 	Quiet bool
+	// Binary caches the spec in the binary format (see Spec.MarshalBinary)
+	// instead of JSON, which loads considerably faster. Binary caches written
+	// by another BinaryFormatVersion are ignored and re-downloaded.
+	Binary bool
 }
 
 // FetchSpec downloads and parses the CloudFormation spec.
@@ -35,14 +98,20 @@ func FetchSpec(opts *FetchOptions) (*Spec, error) {
 	if opts == nil {
 		opts = &FetchOptions{}
 	}
+	if opts.Region == "" {
+		opts.Region = DefaultRegion
+	}
+	if opts.Version == "" {
+		opts.Version = LatestVersion
+	}
 	if opts.URL == "" {
-		opts.URL = DefaultSpecURL
+		opts.URL = SpecURL(opts.Region, opts.Version)
 	}
 	if opts.CacheDir == "" {
 		opts.CacheDir = filepath.Join(os.TempDir(), "cloudformation-schema-go")
 	}
 
-	cachePath := filepath.Join(opts.CacheDir, "spec.json")
+	cachePath := filepath.Join(opts.CacheDir, cacheFileName(opts.Region, opts.Version, opts.Binary))
 
 	// Check for cached spec
 	if !opts.Force {
@@ -52,7 +121,7 @@ func FetchSpec(opts *FetchOptions) (*Spec, error) {
 			if cacheValid {
 				if data, err := os.ReadFile(cachePath); err == nil {
 					var spec Spec
-					if err := json.Unmarshal(data, &spec); err == nil {
+					if err := decodeCache(data, &spec, opts.Binary); err == nil {
 						if !opts.Quiet {
 							fmt.Println("Using cached spec...")
 						}
@@ -109,13 +178,35 @@ func FetchSpec(opts *FetchOptions) (*Spec, error) {
 	}
 
 	// Cache the spec
-	if err := os.MkdirAll(opts.CacheDir, 0755); err == nil {
-		_ = os.WriteFile(cachePath, data, 0644)
+	if opts.Binary {
+		data, err = spec.MarshalBinary()
+	}
+	if err == nil {
+		if err := os.MkdirAll(opts.CacheDir, 0755); err == nil {
+			_ = os.WriteFile(cachePath, data, 0644)
+		}
 	}
 
 	return &spec, nil
 }
 
+// cacheFileName returns the cache file name for a region and version.
+func cacheFileName(region, version string, binary bool) string {
+	ext := "json"
+	if binary {
+		ext = "bin"
+	}
+	return fmt.Sprintf("spec-%s-%s.%s", region, version, ext)
+}
+
+// decodeCache decodes a cached spec in JSON or binary format.
+func decodeCache(data []byte, spec *Spec, binary bool) error {
+	if binary {
+		return spec.UnmarshalBinary(data)
+	}
+	return json.Unmarshal(data, spec)
+}
+
 // LoadSpec loads a spec from a JSON file.
 func LoadSpec(path string) (*Spec, error) {
 	data, err := os.ReadFile(path)
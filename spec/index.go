@@ -0,0 +1,360 @@
+package spec
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Index answers queries over a spec. It is built once with NewIndex and
+// all results are sorted, so repeated queries are deterministic.
+//
+// The index does not observe later changes to the spec; build a new
+// index after modifying it.
+type Index struct {
+	spec           *Spec
+	resourceTypes  []string
+	propertyTypes  []string
+	services       []string
+	byService      map[string][]string
+	byAttribute    map[string][]string
+	byType         map[string][]PropertyRef
+	propertyNames  []string
+	documentation  []DocumentationMatch
+	documentTokens []string
+}
+
+// PropertyRef identifies a property on a resource or property type.
+type PropertyRef struct {
+	TypeName string // owning resource or property type
+	Name     string // property name
+}
+
+// Path returns "TypeName.Name".
+func (r PropertyRef) Path() string {
+	return r.TypeName + "." + r.Name
+}
+
+// DocumentationMatch is a result of a documentation search. Property is
+// empty when the match is the type itself.
+type DocumentationMatch struct {
+	TypeName      string
+	Property      string
+	Documentation string
+}
+
+// NewIndex builds an index over the spec.
+func NewIndex(s *Spec) *Index {
+	idx := &Index{
+		spec:        s,
+		byService:   make(map[string][]string),
+		byAttribute: make(map[string][]string),
+		byType:      make(map[string][]PropertyRef),
+	}
+
+	idx.resourceTypes = sortedKeys(s.ResourceTypes)
+	idx.propertyTypes = sortedKeys(s.PropertyTypes)
+
+	names := make(map[string]bool)
+	services := make(map[string]string)
+
+	addProperties := func(owner, resourceType string, props map[string]Property) {
+		for _, name := range sortedKeys(props) {
+			prop := props[name]
+			names[name] = true
+			ref := PropertyRef{TypeName: owner, Name: name}
+			for _, key := range idx.typeKeys(resourceType, prop) {
+				idx.byType[key] = append(idx.byType[key], ref)
+			}
+			idx.addDocumentation(DocumentationMatch{TypeName: owner, Property: name, Documentation: prop.Documentation})
+		}
+	}
+
+	for _, name := range idx.resourceTypes {
+		rt := s.ResourceTypes[name]
+		if service := ServiceName(name); service != "" {
+			key := strings.ToLower(service)
+			services[key] = service
+			idx.byService[key] = append(idx.byService[key], name)
+		}
+		for attr := range rt.Attributes {
+			idx.byAttribute[attr] = append(idx.byAttribute[attr], name)
+		}
+		idx.addDocumentation(DocumentationMatch{TypeName: name, Documentation: rt.Documentation})
+		addProperties(name, name, rt.Properties)
+	}
+
+	for _, name := range idx.propertyTypes {
+		pt := s.PropertyTypes[name]
+		resourceType, _ := ParsePropertyTypeName(name)
+		idx.addDocumentation(DocumentationMatch{TypeName: name, Documentation: pt.Documentation})
+		addProperties(name, resourceType, pt.Properties)
+	}
+
+	for _, service := range services {
+		idx.services = append(idx.services, service)
+	}
+	sort.Strings(idx.services)
+	for attr := range idx.byAttribute {
+		sort.Strings(idx.byAttribute[attr])
+	}
+	for key := range idx.byType {
+		refs := idx.byType[key]
+		sort.Slice(refs, func(i, j int) bool { return refs[i].Path() < refs[j].Path() })
+	}
+	idx.propertyNames = sortedKeys(names)
+
+	return idx
+}
+
+// typeKeys returns the keys a property is indexed under in PropertiesOfType.
+func (idx *Index) typeKeys(resourceType string, prop Property) []string {
+	keys := make(map[string]bool)
+	for _, k := range []string{prop.PrimitiveType, prop.Type, prop.ItemType, prop.PrimitiveItemType} {
+		if k != "" {
+			keys[k] = true
+		}
+	}
+	for _, k := range []string{prop.Type, prop.ItemType} {
+		if k == "" || k == "List" || k == "Map" {
+			continue
+		}
+		if fullName, pt := idx.spec.ResolvePropertyType(resourceType, k); pt != nil {
+			keys[fullName] = true
+		}
+	}
+	return sortedKeys(keys)
+}
+
+func (idx *Index) addDocumentation(m DocumentationMatch) {
+	idx.documentation = append(idx.documentation, m)
+	idx.documentTokens = append(idx.documentTokens, strings.ToLower(m.TypeName+" "+m.Property+" "+m.Documentation))
+}
+
+// Spec returns the indexed spec.
+func (idx *Index) Spec() *Spec {
+	return idx.spec
+}
+
+// ResourceTypeNames returns all resource type names, sorted.
+func (idx *Index) ResourceTypeNames() []string {
+	return append([]string(nil), idx.resourceTypes...)
+}
+
+// PropertyTypeNames returns all property type names, sorted.
+func (idx *Index) PropertyTypeNames() []string {
+	return append([]string(nil), idx.propertyTypes...)
+}
+
+// Services returns the service names of all resource types (e.g. "EC2", "S3"), sorted.
+func (idx *Index) Services() []string {
+	return append([]string(nil), idx.services...)
+}
+
+// ResourceTypesByService returns the resource types of a service, sorted.
+// The service name is matched case-insensitively, so "EC2" and "ec2" are equivalent.
+func (idx *Index) ResourceTypesByService(service string) []string {
+	return append([]string(nil), idx.byService[strings.ToLower(service)]...)
+}
+
+// ResourceTypesWithAttribute returns the resource types that have the given attribute, sorted.
+func (idx *Index) ResourceTypesWithAttribute(attribute string) []string {
+	return append([]string(nil), idx.byAttribute[attribute]...)
+}
+
+// PropertiesOfType returns the properties whose type matches typeName, sorted by path.
+// typeName may be a primitive type ("String"), "List" or "Map", an item type,
+// or a property type by short ("Tag") or full ("AWS::S3::Bucket.CorsConfiguration") name.
+func (idx *Index) PropertiesOfType(typeName string) []PropertyRef {
+	return append([]PropertyRef(nil), idx.byType[typeName]...)
+}
+
+// PropertyNames returns all distinct property names, sorted.
+func (idx *Index) PropertyNames() []string {
+	return append([]string(nil), idx.propertyNames...)
+}
+
+// MatchPropertyNames returns the distinct property names matching re, sorted.
+func (idx *Index) MatchPropertyNames(re *regexp.Regexp) []string {
+	var result []string
+	for _, name := range idx.propertyNames {
+		if re.MatchString(name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// FuzzyPropertyNames returns the distinct property names that fuzzily match
+// query, best match first. Matching is case-insensitive; exact matches rank
+// above prefixes, prefixes above substrings and substrings above
+// subsequences ("bktnm" matches "BucketName"). Ties are broken by length and name.
+func (idx *Index) FuzzyPropertyNames(query string) []string {
+	return fuzzyRank(idx.propertyNames, query)
+}
+
+// SearchDocumentation returns the types and properties whose name or
+// documentation URL contains every whitespace-separated term of query,
+// case-insensitively. Results are sorted by type name and property.
+func (idx *Index) SearchDocumentation(query string) []DocumentationMatch {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	var result []DocumentationMatch
+	for i, text := range idx.documentTokens {
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, idx.documentation[i])
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TypeName != result[j].TypeName {
+			return result[i].TypeName < result[j].TypeName
+		}
+		return result[i].Property < result[j].Property
+	})
+	return result
+}
+
+// SuggestResourceTypes returns up to max resource type names close to name,
+// closest first, for "did you mean" messages. Names are compared
+// case-insensitively by edit distance; exact matches are not suggested.
+func (idx *Index) SuggestResourceTypes(name string, max int) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	query := strings.ToLower(name)
+	threshold := len(query) / 3
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	var candidates []candidate
+	for _, rt := range idx.resourceTypes {
+		if rt == name {
+			continue
+		}
+		d := editDistance(query, strings.ToLower(rt))
+		if d <= threshold {
+			candidates = append(candidates, candidate{rt, d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var result []string
+	for _, c := range candidates {
+		if max > 0 && len(result) == max {
+			break
+		}
+		result = append(result, c.name)
+	}
+	return result
+}
+
+// ServiceName returns the service of a resource or property type name,
+// e.g. "S3" for "AWS::S3::Bucket". Returns "" if the name has no service.
+func ServiceName(typeName string) string {
+	parts := strings.Split(typeName, "::")
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+// fuzzyRank returns the names matching query ranked by match quality.
+func fuzzyRank(names []string, query string) []string {
+	type candidate struct {
+		name string
+		rank int
+	}
+
+	q := strings.ToLower(query)
+	var candidates []candidate
+	for _, name := range names {
+		n := strings.ToLower(name)
+		rank := -1
+		switch {
+		case n == q:
+			rank = 0
+		case strings.HasPrefix(n, q):
+			rank = 1
+		case strings.Contains(n, q):
+			rank = 2
+		case isSubsequence(q, n):
+			rank = 3
+		}
+		if rank >= 0 {
+			candidates = append(candidates, candidate{name, rank})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if len(a.name) != len(b.name) {
+			return len(a.name) < len(b.name)
+		}
+		return a.name < b.name
+	})
+
+	result := make([]string, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.name
+	}
+	return result
+}
+
+// isSubsequence returns true if every rune of sub appears in s in order.
+func isSubsequence(sub, s string) bool {
+	if sub == "" {
+		return true
+	}
+	subRunes := []rune(sub)
+	i := 0
+	for _, r := range s {
+		if r == subRunes[i] {
+			i++
+			if i == len(subRunes) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
@@ -0,0 +1,178 @@
+package spec_test
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+func TestIndex_Lookups(t *testing.T) {
+	idx := spec.NewIndex(loadSnapshot(t))
+
+	if got, want := idx.ResourceTypesByService("ec2"), []string{"AWS::EC2::Instance", "AWS::EC2::SecurityGroup", "AWS::EC2::Subnet", "AWS::EC2::VPC"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ResourceTypesByService(ec2) = %v, want %v", got, want)
+	}
+	if got := idx.ResourceTypesByService("EC2"); len(got) != 4 {
+		t.Errorf("expected service lookup to be case-insensitive, got %v", got)
+	}
+
+	withArn := idx.ResourceTypesWithAttribute("Arn")
+	for _, want := range []string{"AWS::IAM::Role", "AWS::Lambda::Function", "AWS::S3::Bucket"} {
+		if !contains(withArn, want) {
+			t.Errorf("expected %s in ResourceTypesWithAttribute(Arn) = %v", want, withArn)
+		}
+	}
+	if contains(withArn, "AWS::EC2::VPC") {
+		t.Error("expected AWS::EC2::VPC not to have an Arn attribute")
+	}
+
+	services := idx.Services()
+	if !contains(services, "EC2") || !contains(services, "S3") {
+		t.Errorf("unexpected services %v", services)
+	}
+	if names := idx.ResourceTypeNames(); len(names) == 0 || !sortedStrings(names) {
+		t.Errorf("expected sorted resource type names, got %v", names)
+	}
+}
+
+func TestIndex_PropertiesOfType(t *testing.T) {
+	idx := spec.NewIndex(loadSnapshot(t))
+
+	// Shared property type by short name.
+	tags := idx.PropertiesOfType("Tag")
+	if !containsRef(tags, spec.PropertyRef{TypeName: "AWS::S3::Bucket", Name: "Tags"}) {
+		t.Errorf("expected AWS::S3::Bucket.Tags in %v", tags)
+	}
+
+	// Property type by full name.
+	enc := idx.PropertiesOfType("AWS::S3::Bucket.ServerSideEncryptionByDefault")
+	want := []spec.PropertyRef{{TypeName: "AWS::S3::Bucket.ServerSideEncryptionRule", Name: "ServerSideEncryptionByDefault"}}
+	if !reflect.DeepEqual(enc, want) {
+		t.Errorf("PropertiesOfType(full name) = %v, want %v", enc, want)
+	}
+
+	// Primitive types match directly.
+	jsonProps := idx.PropertiesOfType("Json")
+	if !containsRef(jsonProps, spec.PropertyRef{TypeName: "AWS::IAM::Role", Name: "AssumeRolePolicyDocument"}) {
+		t.Errorf("expected AssumeRolePolicyDocument in Json properties %v", jsonProps)
+	}
+	for i := 1; i < len(tags); i++ {
+		if tags[i-1].Path() > tags[i].Path() {
+			t.Fatalf("expected results sorted by path, got %v", tags)
+		}
+	}
+}
+
+func TestIndex_PropertyNameQueries(t *testing.T) {
+	idx := spec.NewIndex(loadSnapshot(t))
+
+	got := idx.MatchPropertyNames(regexp.MustCompile(`^Bucket`))
+	if want := []string{"BucketEncryption", "BucketKeyEnabled", "BucketName"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchPropertyNames(^Bucket) = %v, want %v", got, want)
+	}
+
+	fuzzy := idx.FuzzyPropertyNames("bktnm")
+	if len(fuzzy) == 0 || fuzzy[0] != "BucketName" {
+		t.Errorf("FuzzyPropertyNames(bktnm) = %v, want BucketName first", fuzzy)
+	}
+	ranked := idx.FuzzyPropertyNames("vpcid")
+	if len(ranked) == 0 || ranked[0] != "VpcId" {
+		t.Errorf("expected exact match first, got %v", ranked)
+	}
+}
+
+func TestIndex_SearchDocumentation(t *testing.T) {
+	idx := spec.NewIndex(loadSnapshot(t))
+
+	matches := idx.SearchDocumentation("s3 bucket sse")
+	if len(matches) == 0 {
+		t.Fatal("expected documentation matches")
+	}
+	found := false
+	for _, m := range matches {
+		if m.TypeName == "AWS::S3::Bucket.ServerSideEncryptionByDefault" && m.Property == "SSEAlgorithm" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected SSEAlgorithm in matches %v", matches)
+	}
+	if again := idx.SearchDocumentation("s3 bucket sse"); !reflect.DeepEqual(matches, again) {
+		t.Error("expected deterministic search results")
+	}
+	if idx.SearchDocumentation("  ") != nil {
+		t.Error("expected empty query to match nothing")
+	}
+}
+
+func TestIndex_SuggestResourceTypes(t *testing.T) {
+	idx := spec.NewIndex(loadSnapshot(t))
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"typo", "AWS::S3::Buckt", "AWS::S3::Bucket"},
+		{"case", "aws::s3::bucket", "AWS::S3::Bucket"},
+		{"plural", "AWS::SQS::Queues", "AWS::SQS::Queue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := idx.SuggestResourceTypes(tt.input, 3)
+			if len(got) == 0 || got[0] != tt.want {
+				t.Errorf("SuggestResourceTypes(%q) = %v, want %s first", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if got := idx.SuggestResourceTypes("AWS::S3::Bucket", 3); contains(got, "AWS::S3::Bucket") {
+		t.Errorf("expected exact match not to be suggested, got %v", got)
+	}
+	if got := idx.SuggestResourceTypes("Something::Else::Entirely", 3); len(got) != 0 {
+		t.Errorf("expected no suggestions, got %v", got)
+	}
+}
+
+func TestServiceName(t *testing.T) {
+	tests := map[string]string{
+		"AWS::S3::Bucket":                   "S3",
+		"AWS::S3::Bucket.CorsConfiguration": "S3",
+		"MyOrg::Network::Vpc":               "Network",
+		"Tag":                               "",
+	}
+	for input, want := range tests {
+		if got := spec.ServiceName(input); got != want {
+			t.Errorf("ServiceName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRef(list []spec.PropertyRef, ref spec.PropertyRef) bool {
+	for _, v := range list {
+		if v == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedStrings(list []string) bool {
+	for i := 1; i < len(list); i++ {
+		if list[i-1] > list[i] {
+			return false
+		}
+	}
+	return true
+}
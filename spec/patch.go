@@ -0,0 +1,523 @@
+package spec
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed patches/*.json
+var bundledPatches embed.FS
+
+// PatchOperation is a single RFC 6902 JSON Patch operation.
+type PatchOperation struct {
+	Op    string          `json:"op"`              // add, remove, replace, move, copy, test
+	Path  string          `json:"path"`            // JSON Pointer (RFC 6901)
+	From  string          `json:"from,omitempty"`  // source pointer for move and copy
+	Value json.RawMessage `json:"value,omitempty"` // value for add, replace and test
+}
+
+// Patch is a JSON Patch document targeted at a resource or property type.
+// Operation paths are relative to the target's definition, e.g.
+// "/Attributes/CidrBlock". If neither ResourceType nor PropertyType is set,
+// paths are relative to the spec root, which allows adding missing types.
+//
+// Example file:
+//
+//	{
+//	  "ResourceType": "AWS::EC2::Subnet",
+//	  "Patch": [
+//	    {"op": "test", "path": "/Attributes/SubnetId/PrimitiveType", "value": "String"},
+//	    {"op": "add", "path": "/Attributes/CidrBlock", "value": {"PrimitiveType": "String"}}
+//	  ]
+//	}
+type Patch struct {
+	Name         string           `json:"-"` // source of the patch, used in reports
+	Description  string           `json:"Description,omitempty"`
+	ResourceType string           `json:"ResourceType,omitempty"`
+	PropertyType string           `json:"PropertyType,omitempty"`
+	Operations   []PatchOperation `json:"Patch"`
+}
+
+// PatchError reports a patch that could not be applied.
+// The spec is left unchanged by a failed patch.
+type PatchError struct {
+	Patch     string // patch name
+	Operation int    // index of the failing operation, -1 if the patch failed as a whole
+	Err       error
+}
+
+// Error implements the error interface.
+func (e *PatchError) Error() string {
+	if e.Operation < 0 {
+		return fmt.Sprintf("patch %s: %v", e.Patch, e.Err)
+	}
+	return fmt.Sprintf("patch %s: operation %d: %v", e.Patch, e.Operation, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyPatches applies patches to the spec in order.
+// Each patch is applied atomically: if any of its operations fail the
+// target is left unchanged. Patches that no longer apply are returned.
+func ApplyPatches(s *Spec, patches []Patch) []*PatchError {
+	var errs []*PatchError
+	for _, p := range patches {
+		if err := applyPatch(s, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// LoadPatches loads patch files (*.json) from a directory in name order.
+// A file may contain a single patch object or an array of patches.
+func LoadPatches(dir string) ([]Patch, error) {
+	return loadPatchesFS(os.DirFS(dir), ".", dir)
+}
+
+// BundledPatches returns the community patches shipped with this package.
+// They correct known upstream spec errors and guard their assumptions with
+// "test" operations, so a patch that no longer applies usually means the
+// upstream spec has been fixed.
+func BundledPatches() ([]Patch, error) {
+	return loadPatchesFS(bundledPatches, "patches", "patches")
+}
+
+func loadPatchesFS(fsys fs.FS, dir, displayDir string) ([]Patch, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading patch directory: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var patches []Patch
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading patch file: %w", err)
+		}
+		filePatches, err := parsePatches(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing patch file %s: %w", filepath.Join(displayDir, name), err)
+		}
+		for i := range filePatches {
+			filePatches[i].Name = name
+			if len(filePatches) > 1 {
+				filePatches[i].Name = fmt.Sprintf("%s[%d]", name, i)
+			}
+		}
+		patches = append(patches, filePatches...)
+	}
+	return patches, nil
+}
+
+func parsePatches(data []byte) ([]Patch, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var patches []Patch
+		if err := json.Unmarshal(trimmed, &patches); err != nil {
+			return nil, err
+		}
+		return patches, nil
+	}
+	var p Patch
+	if err := json.Unmarshal(trimmed, &p); err != nil {
+		return nil, err
+	}
+	return []Patch{p}, nil
+}
+
+func applyPatch(s *Spec, p Patch) *PatchError {
+	fail := func(op int, err error) *PatchError {
+		return &PatchError{Patch: p.Name, Operation: op, Err: err}
+	}
+
+	var target any
+	switch {
+	case p.ResourceType != "" && p.PropertyType != "":
+		return fail(-1, fmt.Errorf("both ResourceType and PropertyType are set"))
+	case p.ResourceType != "":
+		rt, ok := s.ResourceTypes[p.ResourceType]
+		if !ok {
+			return fail(-1, fmt.Errorf("resource type %s not found", p.ResourceType))
+		}
+		target = rt
+	case p.PropertyType != "":
+		pt, ok := s.PropertyTypes[p.PropertyType]
+		if !ok {
+			return fail(-1, fmt.Errorf("property type %s not found", p.PropertyType))
+		}
+		target = pt
+	default:
+		target = s
+	}
+
+	doc, err := toDocument(target)
+	if err != nil {
+		return fail(-1, err)
+	}
+	targetType := reflect.TypeOf(target)
+	for i, op := range p.Operations {
+		doc, err = applyOperation(doc, op, targetType)
+		if err != nil {
+			return fail(i, err)
+		}
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return fail(-1, err)
+	}
+
+	switch {
+	case p.ResourceType != "":
+		var rt ResourceType
+		if err := json.Unmarshal(data, &rt); err != nil {
+			return fail(-1, fmt.Errorf("patched resource type is invalid: %w", err))
+		}
+		s.ResourceTypes[p.ResourceType] = rt
+	case p.PropertyType != "":
+		var pt PropertyType
+		if err := json.Unmarshal(data, &pt); err != nil {
+			return fail(-1, fmt.Errorf("patched property type is invalid: %w", err))
+		}
+		s.PropertyTypes[p.PropertyType] = pt
+	default:
+		var patched Spec
+		if err := json.Unmarshal(data, &patched); err != nil {
+			return fail(-1, fmt.Errorf("patched spec is invalid: %w", err))
+		}
+		*s = patched
+	}
+	return nil
+}
+
+// toDocument converts a value to its generic JSON form.
+func toDocument(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// applyOperation applies op to doc, the JSON form of a value of type typ.
+func applyOperation(doc any, op PatchOperation, typ reflect.Type) (any, error) {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%s requires a value", op.Op)
+		}
+		var value any
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+		switch op.Op {
+		case "add":
+			return pointerAdd(materializeParents(doc, typ, op.Path), op.Path, value)
+		case "replace":
+			if _, err := pointerGet(doc, op.Path); err != nil {
+				return nil, err
+			}
+			doc, _, err := pointerRemove(doc, op.Path)
+			if err != nil {
+				return nil, err
+			}
+			return pointerAdd(doc, op.Path, value)
+		default:
+			current, err := pointerGet(doc, op.Path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, fmt.Errorf("test failed at %s: got %s", op.Path, mustJSON(current))
+			}
+			return doc, nil
+		}
+
+	case "remove":
+		doc, _, err := pointerRemove(doc, op.Path)
+		return doc, err
+
+	case "move":
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %s into itself", op.From)
+		}
+		doc, value, err := pointerRemove(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(materializeParents(doc, typ, op.Path), op.Path, value)
+
+	case "copy":
+		value, err := pointerGet(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		copied, err := toDocument(value)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(materializeParents(doc, typ, op.Path), op.Path, copied)
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// parsePointer splits a JSON Pointer into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func pointerGet(doc any, pointer string) (any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	current := doc
+	for _, t := range tokens {
+		switch node := current.(type) {
+		case map[string]any:
+			v, ok := node[t]
+			if !ok {
+				return nil, fmt.Errorf("path %s not found", pointer)
+			}
+			current = v
+		case []any:
+			i, err := arrayIndex(t, len(node), false)
+			if err != nil {
+				return nil, fmt.Errorf("path %s: %w", pointer, err)
+			}
+			current = node[i]
+		default:
+			return nil, fmt.Errorf("path %s not found", pointer)
+		}
+	}
+	return current, nil
+}
+
+// pointerAdd adds value at pointer and returns the updated document.
+func pointerAdd(doc any, pointer string, value any) (any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parent, err := pointerGet(doc, joinPointer(tokens[:len(tokens)-1]))
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+		return doc, nil
+	case []any:
+		i, err := arrayIndex(last, len(node), true)
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", pointer, err)
+		}
+		node = append(node, nil)
+		copy(node[i+1:], node[i:])
+		node[i] = value
+		return pointerSet(doc, tokens[:len(tokens)-1], node)
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("path %s: parent %s is null", pointer, joinPointer(tokens[:len(tokens)-1]))
+	}
+	return nil, fmt.Errorf("path %s: parent is not an object or array", pointer)
+}
+
+// materializeParents creates the objects on the way to the parent of
+// pointer that are missing or null in doc but are map or struct fields of
+// the spec types, such as the Attributes of a resource type that has none.
+// Map entries, such as a property, are never created.
+func materializeParents(doc any, typ reflect.Type, pointer string) any {
+	tokens, err := parsePointer(pointer)
+	if err != nil || len(tokens) == 0 {
+		return doc
+	}
+	node, ok := doc.(map[string]any)
+	if !ok {
+		return doc
+	}
+	for _, t := range tokens[:len(tokens)-1] {
+		field := indirect(typ).Kind() == reflect.Struct
+		typ = childType(typ, t)
+		if typ == nil {
+			return doc
+		}
+		child, ok := node[t].(map[string]any)
+		if !ok {
+			kind := indirect(typ).Kind()
+			if node[t] != nil || !field || (kind != reflect.Map && kind != reflect.Struct) {
+				return doc
+			}
+			child = map[string]any{}
+			node[t] = child
+		}
+		node = child
+	}
+	return doc
+}
+
+// childType returns the type of the member token of a struct or map type,
+// following JSON field names, or nil if there is none.
+func childType(typ reflect.Type, token string) reflect.Type {
+	typ = indirect(typ)
+	switch typ.Kind() {
+	case reflect.Map:
+		return typ.Elem()
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "" {
+				name = f.Name
+			}
+			if name == token && f.IsExported() {
+				return f.Type
+			}
+		}
+	}
+	return nil
+}
+
+func indirect(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// pointerRemove removes the value at pointer and returns the updated document and removed value.
+func pointerRemove(doc any, pointer string) (any, any, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+	parent, err := pointerGet(doc, joinPointer(tokens[:len(tokens)-1]))
+	if err != nil {
+		return nil, nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		v, ok := node[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("path %s not found", pointer)
+		}
+		delete(node, last)
+		return doc, v, nil
+	case []any:
+		i, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("path %s: %w", pointer, err)
+		}
+		v := node[i]
+		node = append(node[:i:i], node[i+1:]...)
+		doc, err = pointerSet(doc, tokens[:len(tokens)-1], node)
+		return doc, v, err
+	}
+	return nil, nil, fmt.Errorf("path %s not found", pointer)
+}
+
+// pointerSet replaces the value at the given tokens, used when a slice header changes.
+func pointerSet(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parent, err := pointerGet(doc, joinPointer(tokens[:len(tokens)-1]))
+	if err != nil {
+		return nil, err
+	}
+	last := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+	case []any:
+		i, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = value
+	}
+	return doc, nil
+}
+
+func joinPointer(tokens []string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(t, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// arrayIndex parses an array reference token. "-" refers to the end of the
+// array and is only valid when adding.
+func arrayIndex(token string, length int, adding bool) (int, error) {
+	if token == "-" && adding {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	limit := length - 1
+	if adding {
+		limit = length
+	}
+	if i > limit {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+func mustJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,252 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+	"github.com/lex00/cloudformation-schema-go/specembed"
+)
+
+func op(t *testing.T, o, path string, value any) spec.PatchOperation {
+	t.Helper()
+	operation := spec.PatchOperation{Op: o, Path: path}
+	if value != nil {
+		data, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("failed to marshal value: %v", err)
+		}
+		operation.Value = data
+	}
+	return operation
+}
+
+func TestApplyPatches(t *testing.T) {
+	s := loadTestSpec(t)
+
+	patches := []spec.Patch{
+		{
+			Name:         "required",
+			ResourceType: "AWS::S3::Bucket",
+			Operations: []spec.PatchOperation{
+				op(t, "test", "/Properties/BucketName/Required", false),
+				op(t, "replace", "/Properties/BucketName/Required", true),
+				op(t, "add", "/Attributes/WebsiteURL", map[string]any{"PrimitiveType": "String"}),
+				op(t, "remove", "/Attributes/DomainName", nil),
+			},
+		},
+		{
+			Name:         "property_type",
+			PropertyType: "AWS::S3::Bucket.CorsConfiguration",
+			Operations: []spec.PatchOperation{
+				{Op: "copy", From: "/Properties/CorsRules", Path: "/Properties/Rules"},
+				{Op: "move", From: "/Properties/Rules", Path: "/Properties/LegacyRules"},
+			},
+		},
+		{
+			Name: "root",
+			Operations: []spec.PatchOperation{
+				op(t, "add", "/ResourceTypes/AWS::SQS::Queue", map[string]any{
+					"Properties": map[string]any{"QueueName": map[string]any{"PrimitiveType": "String"}},
+				}),
+			},
+		},
+	}
+
+	if errs := spec.ApplyPatches(s, patches); len(errs) != 0 {
+		t.Fatalf("unexpected patch errors: %v", errs)
+	}
+
+	bucket := s.GetResourceType("AWS::S3::Bucket")
+	if !bucket.GetProperty("BucketName").Required {
+		t.Error("expected BucketName to be required after patch")
+	}
+	if !bucket.HasAttribute("WebsiteURL") || bucket.HasAttribute("DomainName") {
+		t.Errorf("unexpected attributes after patch: %v", bucket.AttributeNames())
+	}
+	cors := s.GetPropertyType("AWS::S3::Bucket.CorsConfiguration")
+	if !cors.HasProperty("CorsRules") || !cors.HasProperty("LegacyRules") || cors.HasProperty("Rules") {
+		t.Errorf("unexpected CorsConfiguration properties after patch: %v", cors.Properties)
+	}
+	if !s.HasResourceType("AWS::SQS::Queue") {
+		t.Error("expected root patch to add AWS::SQS::Queue")
+	}
+}
+
+func TestApplyPatches_NoLongerApplies(t *testing.T) {
+	s := loadTestSpec(t)
+
+	patches := []spec.Patch{
+		{
+			Name:         "stale_test",
+			ResourceType: "AWS::S3::Bucket",
+			Operations: []spec.PatchOperation{
+				op(t, "replace", "/Properties/Tags/Required", true),
+				op(t, "test", "/Properties/BucketName/Required", true),
+			},
+		},
+		{
+			Name:         "missing_type",
+			ResourceType: "AWS::SDB::Domain",
+			Operations:   []spec.PatchOperation{op(t, "remove", "/Properties/Description", nil)},
+		},
+		{
+			Name:         "missing_path",
+			ResourceType: "AWS::EC2::Instance",
+			Operations:   []spec.PatchOperation{op(t, "remove", "/Properties/UserData", nil)},
+		},
+	}
+
+	errs := spec.ApplyPatches(s, patches)
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 patch errors, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Patch != "stale_test" || errs[0].Operation != 1 {
+		t.Errorf("expected stale_test to fail at operation 1, got %v", errs[0])
+	}
+	if errs[1].Operation != -1 {
+		t.Errorf("expected missing_type to fail as a whole, got %v", errs[1])
+	}
+
+	// A failed patch leaves the target unchanged.
+	if s.GetResourceType("AWS::S3::Bucket").GetProperty("Tags").Required {
+		t.Error("expected failed patch not to modify the spec")
+	}
+}
+
+func TestApplyPatches_MissingParent(t *testing.T) {
+	var s spec.Spec
+	if err := json.Unmarshal([]byte(`{
+		"ResourceTypes": {
+			"AWS::SDB::Domain": {"Properties": {"Description": {"PrimitiveType": "String"}}},
+			"AWS::CloudFormation::WaitConditionHandle": {"Attributes": null, "Properties": null}
+		},
+		"PropertyTypes": {}
+	}`), &s); err != nil {
+		t.Fatalf("failed to unmarshal spec: %v", err)
+	}
+
+	patches := []spec.Patch{
+		{
+			Name:         "omitted_attributes",
+			ResourceType: "AWS::SDB::Domain",
+			Operations:   []spec.PatchOperation{op(t, "add", "/Attributes/Foo", map[string]any{"PrimitiveType": "String"})},
+		},
+		{
+			Name:         "null_properties",
+			ResourceType: "AWS::CloudFormation::WaitConditionHandle",
+			Operations: []spec.PatchOperation{
+				op(t, "add", "/Properties/Bar", map[string]any{"PrimitiveType": "String"}),
+				op(t, "add", "/Attributes/Url", map[string]any{"PrimitiveType": "String"}),
+			},
+		},
+	}
+	if errs := spec.ApplyPatches(&s, patches); len(errs) != 0 {
+		t.Fatalf("unexpected patch errors: %v", errs)
+	}
+	if !s.GetResourceType("AWS::SDB::Domain").HasAttribute("Foo") {
+		t.Error("expected Foo attribute to be added")
+	}
+	handle := s.GetResourceType("AWS::CloudFormation::WaitConditionHandle")
+	if !handle.HasProperty("Bar") || !handle.HasAttribute("Url") {
+		t.Errorf("expected Bar and Url to be added, got %v", handle)
+	}
+
+	// Entries of maps, such as a property, are not created.
+	errs := spec.ApplyPatches(&s, []spec.Patch{{
+		Name:         "missing_property",
+		ResourceType: "AWS::SDB::Domain",
+		Operations:   []spec.PatchOperation{op(t, "add", "/Properties/Missing/Required", true)},
+	}})
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 patch error, got %v", errs)
+	}
+}
+
+func TestLoadPatches(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.json": `[
+			{"ResourceType": "AWS::S3::Bucket", "Patch": [{"op": "remove", "path": "/Attributes/Arn"}]},
+			{"ResourceType": "AWS::S3::Bucket", "Patch": [{"op": "remove", "path": "/Attributes/DomainName"}]}
+		]`,
+		"a.json":     `{"ResourceType": "AWS::EC2::Instance", "Patch": [{"op": "remove", "path": "/Properties/ImageId"}]}`,
+		"ignore.txt": `not a patch`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	patches, err := spec.LoadPatches(dir)
+	if err != nil {
+		t.Fatalf("LoadPatches failed: %v", err)
+	}
+	var names []string
+	for _, p := range patches {
+		names = append(names, p.Name)
+	}
+	if len(names) != 3 || names[0] != "a.json" || names[1] != "b.json[0]" || names[2] != "b.json[1]" {
+		t.Errorf("unexpected patch names: %v", names)
+	}
+
+	s := loadTestSpec(t)
+	if errs := spec.ApplyPatches(s, patches); len(errs) != 0 {
+		t.Fatalf("unexpected patch errors: %v", errs)
+	}
+	if len(s.GetResourceType("AWS::S3::Bucket").Attributes) != 0 {
+		t.Error("expected bucket attributes to be removed")
+	}
+}
+
+func TestLoadPatches_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0644); err != nil {
+		t.Fatalf("failed to write patch: %v", err)
+	}
+	if _, err := spec.LoadPatches(dir); err == nil {
+		t.Error("expected error for invalid patch file")
+	}
+	if _, err := spec.LoadPatches(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func TestBundledPatches(t *testing.T) {
+	patches, err := spec.BundledPatches()
+	if err != nil {
+		t.Fatalf("BundledPatches failed: %v", err)
+	}
+	if len(patches) == 0 {
+		t.Fatal("expected bundled patches")
+	}
+	for _, p := range patches {
+		if p.ResourceType == "" && p.PropertyType == "" {
+			t.Errorf("bundled patch %s has no target", p.Name)
+		}
+		if p.Description == "" {
+			t.Errorf("bundled patch %s has no description", p.Name)
+		}
+	}
+}
+
+func TestBundledPatches_ApplyToSnapshot(t *testing.T) {
+	patches, err := spec.BundledPatches()
+	if err != nil {
+		t.Fatalf("BundledPatches failed: %v", err)
+	}
+	s, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("loading snapshot: %v", err)
+	}
+
+	for _, err := range spec.ApplyPatches(s, patches) {
+		t.Errorf("bundled patch no longer applies to spec %s: %v", s.ResourceSpecificationVersion, err)
+	}
+	if !s.GetResourceType("AWS::EC2::Subnet").HasAttribute("AvailabilityZoneId") {
+		t.Error("expected the subnet patch to add AvailabilityZoneId")
+	}
+}
@@ -0,0 +1,186 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResolvedPath is the result of resolving a property path against a resource type.
+type ResolvedPath struct {
+	// Path is the path that was resolved, e.g. "BucketEncryption.ServerSideEncryptionConfiguration[*].ServerSideEncryptionByDefault.SSEAlgorithm".
+	Path string
+	// Name is the name of the final property, e.g. "SSEAlgorithm".
+	Name string
+	// Property is the definition of the final property.
+	Property *Property
+	// Owner is the name of the type declaring the final property. This is the
+	// resource type for top-level properties and a property type otherwise.
+	Owner string
+	// OwnerType is the property type declaring the final property.
+	// It is nil for top-level properties.
+	OwnerType *PropertyType
+	// Item is true if the path ends in an item selector ("[*]").
+	Item bool
+	// PrimitiveType is the effective primitive type of the value at the path:
+	// the item type for item selectors and the property type otherwise.
+	// It is empty for lists, maps and property types.
+	PrimitiveType string
+	// TypeName is the full name of the property type of the value at the path,
+	// empty for primitives, lists and maps.
+	TypeName string
+}
+
+// ResolvePath resolves a dotted property path on a resource type.
+// List and map items are selected with "[*]" (or an index or key in brackets).
+// Property types are looked up with the resource prefix first and then
+// without it, which covers the shared "Tag" property type.
+//
+// Example:
+//
+//	r, err := s.ResolvePath("AWS::S3::Bucket",
+//	    "BucketEncryption.ServerSideEncryptionConfiguration[*].ServerSideEncryptionByDefault.SSEAlgorithm")
+//	// r.PrimitiveType == "String", r.Owner == "AWS::S3::Bucket.ServerSideEncryptionByDefault"
+func (s *Spec) ResolvePath(resourceType, path string) (*ResolvedPath, error) {
+	rt := s.GetResourceType(resourceType)
+	if rt == nil {
+		return nil, fmt.Errorf("resource type %s not found", resourceType)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("empty property path")
+	}
+
+	owner := resourceType
+	var ownerType *PropertyType
+	props := rt.Properties
+
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		name, item, err := parsePathSegment(segment)
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", path, err)
+		}
+		prop, ok := props[name]
+		if !ok {
+			return nil, fmt.Errorf("path %s: %s has no property %s", path, owner, name)
+		}
+		if item && !prop.IsList() && !prop.IsMap() {
+			return nil, fmt.Errorf("path %s: property %s is not a list or map", path, name)
+		}
+
+		// The type name of the value selected by this segment.
+		typeName := prop.Type
+		primitive := prop.PrimitiveType
+		if item {
+			typeName = prop.ItemType
+			primitive = prop.PrimitiveItemType
+		}
+
+		if i == len(segments)-1 {
+			r := &ResolvedPath{
+				Path:      path,
+				Name:      name,
+				Property:  &prop,
+				Owner:     owner,
+				OwnerType: ownerType,
+				Item:      item,
+			}
+			switch {
+			case primitive != "":
+				r.PrimitiveType = primitive
+			case typeName != "" && typeName != "List" && typeName != "Map":
+				r.TypeName, _ = s.ResolvePropertyType(resourceType, typeName)
+			}
+			return r, nil
+		}
+
+		if !item && (prop.IsList() || prop.IsMap()) {
+			return nil, fmt.Errorf("path %s: property %s is a %s; select its items with %s[*]", path, name, prop.Type, name)
+		}
+		if typeName == "" {
+			return nil, fmt.Errorf("path %s: property %s is a primitive and has no nested properties", path, name)
+		}
+		fullName, pt := s.ResolvePropertyType(resourceType, typeName)
+		if pt == nil {
+			return nil, fmt.Errorf("path %s: property type %s not found", path, typeName)
+		}
+		owner = fullName
+		ownerType = pt
+		props = pt.Properties
+	}
+
+	// Unreachable: the loop returns on the last segment.
+	return nil, fmt.Errorf("path %s could not be resolved", path)
+}
+
+// ResolvePropertyType looks up a property type referenced from a resource
+// type's properties. It tries "resourceType.typeName" first and then the
+// bare name, which covers shared property types such as "Tag".
+// Returns the full property type name and definition, or ("", nil) if not found.
+func (s *Spec) ResolvePropertyType(resourceType, typeName string) (string, *PropertyType) {
+	fullName := GetPropertyTypeForResource(resourceType, typeName)
+	if pt := s.GetPropertyType(fullName); pt != nil {
+		return fullName, pt
+	}
+	if pt := s.GetPropertyType(typeName); pt != nil {
+		return typeName, pt
+	}
+	return "", nil
+}
+
+// LeafPaths returns every path on the resource type that resolves to a
+// primitive value, sorted. Lists and maps are traversed with "[*]".
+// Recursive property types are traversed once per path.
+func (s *Spec) LeafPaths(resourceType string) ([]string, error) {
+	rt := s.GetResourceType(resourceType)
+	if rt == nil {
+		return nil, fmt.Errorf("resource type %s not found", resourceType)
+	}
+
+	var paths []string
+	var walk func(prefix string, props map[string]Property, visiting map[string]bool)
+	walk = func(prefix string, props map[string]Property, visiting map[string]bool) {
+		for name, prop := range props {
+			path := prefix + name
+			typeName := prop.Type
+			if prop.IsList() || prop.IsMap() {
+				path += "[*]"
+				typeName = prop.ItemType
+				if prop.PrimitiveItemType != "" {
+					paths = append(paths, path)
+					continue
+				}
+			}
+			if typeName == "" {
+				paths = append(paths, path)
+				continue
+			}
+			fullName, pt := s.ResolvePropertyType(resourceType, typeName)
+			if pt == nil || visiting[fullName] {
+				continue
+			}
+			visiting[fullName] = true
+			walk(path+".", pt.Properties, visiting)
+			delete(visiting, fullName)
+		}
+	}
+	walk("", rt.Properties, make(map[string]bool))
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// parsePathSegment splits "Name[*]" into its property name and item flag.
+func parsePathSegment(segment string) (name string, item bool, err error) {
+	open := strings.IndexByte(segment, '[')
+	if open < 0 {
+		if segment == "" {
+			return "", false, fmt.Errorf("empty path segment")
+		}
+		return segment, false, nil
+	}
+	if !strings.HasSuffix(segment, "]") || open == 0 || open == len(segment)-2 {
+		return "", false, fmt.Errorf("invalid path segment %q", segment)
+	}
+	return segment[:open], true, nil
+}
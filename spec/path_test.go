@@ -0,0 +1,181 @@
+package spec_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSpec_ResolvePath(t *testing.T) {
+	s := loadSnapshot(t)
+
+	tests := []struct {
+		name          string
+		resourceType  string
+		path          string
+		wantOwner     string
+		wantPrimitive string
+		wantTypeName  string
+		wantOwnerType bool
+	}{
+		{
+			name:          "top_level_primitive",
+			resourceType:  "AWS::S3::Bucket",
+			path:          "BucketName",
+			wantOwner:     "AWS::S3::Bucket",
+			wantPrimitive: "String",
+		},
+		{
+			name:          "nested_through_list",
+			resourceType:  "AWS::S3::Bucket",
+			path:          "BucketEncryption.ServerSideEncryptionConfiguration[*].ServerSideEncryptionByDefault.SSEAlgorithm",
+			wantOwner:     "AWS::S3::Bucket.ServerSideEncryptionByDefault",
+			wantPrimitive: "String",
+			wantOwnerType: true,
+		},
+		{
+			name:          "list_item_property_type",
+			resourceType:  "AWS::S3::Bucket",
+			path:          "BucketEncryption.ServerSideEncryptionConfiguration[*]",
+			wantOwner:     "AWS::S3::Bucket.BucketEncryption",
+			wantTypeName:  "AWS::S3::Bucket.ServerSideEncryptionRule",
+			wantOwnerType: true,
+		},
+		{
+			name:          "shared_tag",
+			resourceType:  "AWS::S3::Bucket",
+			path:          "Tags[*].Key",
+			wantOwner:     "Tag",
+			wantPrimitive: "String",
+			wantOwnerType: true,
+		},
+		{
+			name:          "map_of_primitives",
+			resourceType:  "AWS::Lambda::Function",
+			path:          "Environment.Variables[*]",
+			wantOwner:     "AWS::Lambda::Function.Environment",
+			wantPrimitive: "String",
+			wantOwnerType: true,
+		},
+		{
+			name:          "list_index",
+			resourceType:  "AWS::EC2::Instance",
+			path:          "BlockDeviceMappings[0].Ebs.VolumeSize",
+			wantOwner:     "AWS::EC2::Instance.Ebs",
+			wantPrimitive: "Integer",
+			wantOwnerType: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := s.ResolvePath(tt.resourceType, tt.path)
+			if err != nil {
+				t.Fatalf("ResolvePath(%q) failed: %v", tt.path, err)
+			}
+			if r.Owner != tt.wantOwner {
+				t.Errorf("Owner = %q, want %q", r.Owner, tt.wantOwner)
+			}
+			if r.PrimitiveType != tt.wantPrimitive {
+				t.Errorf("PrimitiveType = %q, want %q", r.PrimitiveType, tt.wantPrimitive)
+			}
+			if r.TypeName != tt.wantTypeName {
+				t.Errorf("TypeName = %q, want %q", r.TypeName, tt.wantTypeName)
+			}
+			if (r.OwnerType != nil) != tt.wantOwnerType {
+				t.Errorf("OwnerType set = %v, want %v", r.OwnerType != nil, tt.wantOwnerType)
+			}
+			if r.Property == nil {
+				t.Error("expected Property to be set")
+			}
+		})
+	}
+}
+
+func TestSpec_ResolvePath_Errors(t *testing.T) {
+	s := loadSnapshot(t)
+
+	tests := []struct {
+		name         string
+		resourceType string
+		path         string
+		wantErr      string
+	}{
+		{"unknown_resource", "AWS::S3::Nope", "BucketName", "not found"},
+		{"empty", "AWS::S3::Bucket", "", "empty"},
+		{"unknown_property", "AWS::S3::Bucket", "Nope", "has no property Nope"},
+		{"list_without_selector", "AWS::S3::Bucket", "Tags.Key", "Tags[*]"},
+		{"selector_on_scalar", "AWS::S3::Bucket", "BucketName[*]", "not a list or map"},
+		{"into_primitive", "AWS::S3::Bucket", "BucketName.Length", "primitive"},
+		{"bad_segment", "AWS::S3::Bucket", "Tags[*.Key", "invalid path segment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ResolvePath(tt.resourceType, tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ResolvePath(%q) error = %v, want containing %q", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSpec_ResolvePropertyType(t *testing.T) {
+	s := loadTestSpec(t)
+
+	name, pt := s.ResolvePropertyType("AWS::S3::Bucket", "CorsConfiguration")
+	if pt == nil || name != "AWS::S3::Bucket.CorsConfiguration" {
+		t.Errorf("expected resource-prefixed property type, got %q", name)
+	}
+	if name, pt := s.ResolvePropertyType("AWS::S3::Bucket", "Missing"); pt != nil || name != "" {
+		t.Errorf("expected no property type, got %q", name)
+	}
+}
+
+func TestSpec_LeafPaths(t *testing.T) {
+	s := loadSnapshot(t)
+
+	paths, err := s.LeafPaths("AWS::S3::Bucket")
+	if err != nil {
+		t.Fatalf("LeafPaths failed: %v", err)
+	}
+	for _, want := range []string{
+		"BucketName",
+		"BucketEncryption.ServerSideEncryptionConfiguration[*].ServerSideEncryptionByDefault.SSEAlgorithm",
+		"CorsConfiguration.CorsRules[*].AllowedMethods[*]",
+		"Tags[*].Key",
+		"Tags[*].Value",
+	} {
+		found := false
+		for _, p := range paths {
+			if p == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected leaf path %q in %v", want, paths)
+		}
+	}
+
+	// Every leaf path resolves to a primitive.
+	for _, p := range paths {
+		r, err := s.ResolvePath("AWS::S3::Bucket", p)
+		if err != nil {
+			t.Errorf("leaf path %q does not resolve: %v", p, err)
+			continue
+		}
+		if r.PrimitiveType == "" {
+			t.Errorf("leaf path %q resolves to non-primitive %+v", p, r)
+		}
+	}
+
+	again, _ := s.LeafPaths("AWS::S3::Bucket")
+	if !reflect.DeepEqual(paths, again) {
+		t.Error("expected LeafPaths to be deterministic")
+	}
+
+	if _, err := s.LeafPaths("AWS::S3::Nope"); err == nil {
+		t.Error("expected error for unknown resource type")
+	}
+}
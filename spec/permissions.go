@@ -0,0 +1,103 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Operation is a resource handler operation.
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationRead   Operation = "read"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+	OperationList   Operation = "list"
+)
+
+// Operations lists all handler operations.
+var Operations = []Operation{OperationCreate, OperationRead, OperationUpdate, OperationDelete, OperationList}
+
+// DeploymentOperations are the operations CloudFormation invokes when
+// creating, updating and deleting stacks. List is only used for resource
+// import and discovery.
+var DeploymentOperations = []Operation{OperationCreate, OperationRead, OperationUpdate, OperationDelete}
+
+// Handler is a resource handler declaration.
+type Handler struct {
+	Permissions      []string `json:"Permissions"`
+	TimeoutInMinutes int      `json:"TimeoutInMinutes,omitempty"`
+}
+
+// ErrNoHandlers is returned when a resource type has no handler permissions.
+var ErrNoHandlers = errors.New("no handler permissions")
+
+// RequiredActions returns the IAM actions needed to run the given handler
+// operations on a resource type, sorted and without duplicates. If no
+// operations are given, all handlers are included.
+//
+// Returns an error wrapping ErrNoHandlers if the type has no handler
+// information, which is the case for types from the legacy resource
+// specification until AttachHandlers is called.
+func (s *Spec) RequiredActions(resourceType string, ops ...Operation) ([]string, error) {
+	return RequiredActions(s, resourceType, ops...)
+}
+
+// RequiredActions is like Spec.RequiredActions for any SchemaProvider.
+func RequiredActions(p SchemaProvider, resourceType string, ops ...Operation) ([]string, error) {
+	rt := p.GetResourceType(resourceType)
+	if rt == nil {
+		return nil, fmt.Errorf("resource type %s not found", resourceType)
+	}
+	if len(rt.Handlers) == 0 {
+		return nil, fmt.Errorf("resource type %s: %w", resourceType, ErrNoHandlers)
+	}
+	if len(ops) == 0 {
+		ops = Operations
+	}
+
+	actions := make(map[string]bool)
+	for _, op := range ops {
+		for _, action := range rt.Handlers[op].Permissions {
+			actions[action] = true
+		}
+	}
+	return sortedKeys(actions), nil
+}
+
+// AttachHandlers copies the handler permissions of registry schemas onto
+// the matching resource types, leaving their definitions unchanged. This
+// is how permissions from the AWS registry schemas are added to the
+// resource specification. Returns the schema type names that are not in
+// the spec, sorted.
+func (s *Spec) AttachHandlers(schemas []*RegistrySchema) []string {
+	var missing []string
+	for _, rs := range schemas {
+		rt, ok := s.ResourceTypes[rs.TypeName]
+		if !ok {
+			missing = append(missing, rs.TypeName)
+			continue
+		}
+		rt.Handlers = rs.handlers()
+		s.ResourceTypes[rs.TypeName] = rt
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// handlers converts the schema's handler declarations.
+func (rs *RegistrySchema) handlers() map[Operation]Handler {
+	if len(rs.Handlers) == 0 {
+		return nil
+	}
+	handlers := make(map[Operation]Handler, len(rs.Handlers))
+	for op, h := range rs.Handlers {
+		handlers[Operation(op)] = Handler{
+			Permissions:      append([]string(nil), h.Permissions...),
+			TimeoutInMinutes: h.TimeoutInMinutes,
+		}
+	}
+	return handlers
+}
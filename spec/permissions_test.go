@@ -0,0 +1,81 @@
+package spec_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+func TestSpec_RequiredActions(t *testing.T) {
+	rs, err := spec.ParseRegistrySchema([]byte(registrySchemaJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := rs.ToSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ops  []spec.Operation
+		want []string
+	}{
+		{"create", []spec.Operation{spec.OperationCreate}, []string{"ec2:CreateTags", "ec2:CreateVpc"}},
+		{"create_delete", []spec.Operation{spec.OperationCreate, spec.OperationDelete}, []string{"ec2:CreateTags", "ec2:CreateVpc", "ec2:DeleteVpc"}},
+		{"all", nil, []string{"ec2:CreateTags", "ec2:CreateVpc", "ec2:DeleteVpc"}},
+		{"undeclared", []spec.Operation{spec.OperationList}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.RequiredActions("MyOrg::Network::Vpc", tt.ops...)
+			if err != nil {
+				t.Fatalf("RequiredActions failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpec_AttachHandlers(t *testing.T) {
+	s := loadTestSpec(t)
+
+	if _, err := s.RequiredActions("AWS::S3::Bucket"); !errors.Is(err, spec.ErrNoHandlers) {
+		t.Fatalf("expected ErrNoHandlers before attaching, got %v", err)
+	}
+	if _, err := s.RequiredActions("AWS::S3::Nope"); err == nil || errors.Is(err, spec.ErrNoHandlers) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	bucket, _ := spec.ParseRegistrySchema([]byte(`{
+		"typeName": "AWS::S3::Bucket",
+		"properties": {"Other": {"type": "string"}},
+		"handlers": {"create": {"permissions": ["s3:CreateBucket", "s3:PutBucketTagging"]}}
+	}`))
+	unknown, _ := spec.ParseRegistrySchema([]byte(`{"typeName": "AWS::S3::Nope", "handlers": {}}`))
+
+	missing := s.AttachHandlers([]*spec.RegistrySchema{bucket, unknown})
+	if !reflect.DeepEqual(missing, []string{"AWS::S3::Nope"}) {
+		t.Errorf("missing = %v", missing)
+	}
+	got, err := s.RequiredActions("AWS::S3::Bucket", spec.OperationCreate)
+	if err != nil || !reflect.DeepEqual(got, []string{"s3:CreateBucket", "s3:PutBucketTagging"}) {
+		t.Errorf("RequiredActions = %v, %v", got, err)
+	}
+	if _, ok := s.ResourceTypes["AWS::S3::Bucket"].Properties["Other"]; ok {
+		t.Error("expected AttachHandlers to leave properties unchanged")
+	}
+
+	data, _ := s.MarshalBinary()
+	var decoded spec.Spec
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.ResourceTypes["AWS::S3::Bucket"].Handlers, s.ResourceTypes["AWS::S3::Bucket"].Handlers) {
+		t.Error("expected handlers to survive the binary encoding")
+	}
+}
@@ -0,0 +1,235 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SchemaProvider is a source of resource and property type definitions.
+// It is implemented by *Spec, *LazySpec, *RegistryProvider and
+// *LayeredProvider, and is easy to fake in tests.
+//
+// Returned types may be shared with the provider and must not be modified.
+type SchemaProvider interface {
+	// Version returns the spec version, or "" if there is none.
+	Version() string
+	// ResourceTypeNames returns all resource type names, in any order.
+	ResourceTypeNames() []string
+	// PropertyTypeNames returns all property type names, in any order.
+	PropertyTypeNames() []string
+	// GetResourceType returns a resource type, or nil if it is not defined.
+	// Its Attributes are the values available to Fn::GetAtt.
+	GetResourceType(typeName string) *ResourceType
+	// GetPropertyType returns a property type, or nil if it is not defined.
+	GetPropertyType(typeName string) *PropertyType
+}
+
+var (
+	_ SchemaProvider = (*Spec)(nil)
+	_ SchemaProvider = (*LazySpec)(nil)
+	_ SchemaProvider = (*RegistryProvider)(nil)
+	_ SchemaProvider = (*LayeredProvider)(nil)
+)
+
+// Version returns the ResourceSpecificationVersion of the spec.
+func (s *Spec) Version() string {
+	return s.ResourceSpecificationVersion
+}
+
+// Materialize returns the full spec of a provider. A *Spec is returned
+// as is; other providers are decoded or copied into a new spec.
+func Materialize(p SchemaProvider) (*Spec, error) {
+	switch p := p.(type) {
+	case *Spec:
+		return p, nil
+	case interface{ Spec() (*Spec, error) }:
+		return p.Spec()
+	}
+
+	s := &Spec{
+		ResourceSpecificationVersion: p.Version(),
+		ResourceTypes:                make(map[string]ResourceType),
+		PropertyTypes:                make(map[string]PropertyType),
+	}
+	for _, name := range p.ResourceTypeNames() {
+		if rt := p.GetResourceType(name); rt != nil {
+			s.ResourceTypes[name] = *rt
+		}
+	}
+	for _, name := range p.PropertyTypeNames() {
+		if pt := p.GetPropertyType(name); pt != nil {
+			s.PropertyTypes[name] = *pt
+		}
+	}
+	return s, nil
+}
+
+// RegistryProvider serves registry schemas as a SchemaProvider. Each
+// schema is converted with ToSpec on first use of one of its types.
+type RegistryProvider struct {
+	schemas map[string]*RegistrySchema
+
+	mu        sync.Mutex
+	converted map[string]*Spec
+}
+
+// NewRegistryProvider returns a provider for the schemas. Schemas must
+// have distinct type names.
+func NewRegistryProvider(schemas []*RegistrySchema) (*RegistryProvider, error) {
+	p := &RegistryProvider{
+		schemas:   make(map[string]*RegistrySchema, len(schemas)),
+		converted: make(map[string]*Spec),
+	}
+	for _, rs := range schemas {
+		if _, ok := p.schemas[rs.TypeName]; ok {
+			return nil, fmt.Errorf("duplicate registry schema for %s", rs.TypeName)
+		}
+		p.schemas[rs.TypeName] = rs
+	}
+	return p, nil
+}
+
+// convert returns the converted schema of a type name.
+func (p *RegistryProvider) convert(typeName string) (*Spec, error) {
+	rs, ok := p.schemas[typeName]
+	if !ok {
+		return nil, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if s, ok := p.converted[typeName]; ok {
+		return s, nil
+	}
+	s, err := rs.ToSpec()
+	if err != nil {
+		return nil, err
+	}
+	p.converted[typeName] = s
+	return s, nil
+}
+
+// Version returns "" because registry schemas are not versioned as a whole.
+func (p *RegistryProvider) Version() string {
+	return ""
+}
+
+// ResourceTypeNames returns the type names of the schemas, sorted.
+func (p *RegistryProvider) ResourceTypeNames() []string {
+	return sortedKeys(p.schemas)
+}
+
+// PropertyTypeNames returns the property types of all schemas, sorted.
+// This converts every schema; schemas that fail to convert are skipped.
+func (p *RegistryProvider) PropertyTypeNames() []string {
+	var names []string
+	for _, typeName := range p.ResourceTypeNames() {
+		s, err := p.convert(typeName)
+		if err != nil {
+			continue
+		}
+		names = append(names, s.PropertyTypeNames()...)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetResourceType returns the converted resource type, or nil if there is
+// no schema for it or it fails to convert.
+func (p *RegistryProvider) GetResourceType(typeName string) *ResourceType {
+	s, err := p.convert(typeName)
+	if s == nil || err != nil {
+		return nil
+	}
+	return s.GetResourceType(typeName)
+}
+
+// GetPropertyType returns a converted property type such as
+// "MyOrg::Network::Vpc.Subnet", or nil if it is not defined.
+func (p *RegistryProvider) GetPropertyType(typeName string) *PropertyType {
+	resourceType, _ := ParsePropertyTypeName(typeName)
+	s, err := p.convert(resourceType)
+	if s == nil || err != nil {
+		return nil
+	}
+	return s.GetPropertyType(typeName)
+}
+
+// Spec converts every schema and merges them into one spec, with provenance.
+func (p *RegistryProvider) Spec() (*Spec, error) {
+	s := &Spec{ResourceTypes: map[string]ResourceType{}, PropertyTypes: map[string]PropertyType{}}
+	for _, typeName := range p.ResourceTypeNames() {
+		converted, err := p.convert(typeName)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Merge(converted, nil); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// LayeredProvider combines providers. Types are looked up in each layer
+// in order, so earlier layers override later ones: put registry or test
+// types before the AWS spec.
+type LayeredProvider struct {
+	layers []SchemaProvider
+}
+
+// NewLayeredProvider returns a provider over the layers, highest priority first.
+func NewLayeredProvider(layers ...SchemaProvider) *LayeredProvider {
+	return &LayeredProvider{layers: layers}
+}
+
+// Version returns the first non-empty version of the layers.
+func (p *LayeredProvider) Version() string {
+	for _, layer := range p.layers {
+		if v := layer.Version(); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ResourceTypeNames returns the resource types of all layers, sorted.
+func (p *LayeredProvider) ResourceTypeNames() []string {
+	names := make(map[string]bool)
+	for _, layer := range p.layers {
+		for _, name := range layer.ResourceTypeNames() {
+			names[name] = true
+		}
+	}
+	return sortedKeys(names)
+}
+
+// PropertyTypeNames returns the property types of all layers, sorted.
+func (p *LayeredProvider) PropertyTypeNames() []string {
+	names := make(map[string]bool)
+	for _, layer := range p.layers {
+		for _, name := range layer.PropertyTypeNames() {
+			names[name] = true
+		}
+	}
+	return sortedKeys(names)
+}
+
+// GetResourceType returns the resource type from the first layer defining it.
+func (p *LayeredProvider) GetResourceType(typeName string) *ResourceType {
+	for _, layer := range p.layers {
+		if rt := layer.GetResourceType(typeName); rt != nil {
+			return rt
+		}
+	}
+	return nil
+}
+
+// GetPropertyType returns the property type from the first layer defining it.
+func (p *LayeredProvider) GetPropertyType(typeName string) *PropertyType {
+	for _, layer := range p.layers {
+		if pt := layer.GetPropertyType(typeName); pt != nil {
+			return pt
+		}
+	}
+	return nil
+}
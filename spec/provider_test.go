@@ -0,0 +1,129 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+func registryProvider(t *testing.T) *spec.RegistryProvider {
+	t.Helper()
+	rs, err := spec.ParseRegistrySchema([]byte(registrySchemaJSON))
+	if err != nil {
+		t.Fatalf("ParseRegistrySchema failed: %v", err)
+	}
+	p, err := spec.NewRegistryProvider([]*spec.RegistrySchema{rs})
+	if err != nil {
+		t.Fatalf("NewRegistryProvider failed: %v", err)
+	}
+	return p
+}
+
+func TestRegistryProvider(t *testing.T) {
+	p := registryProvider(t)
+
+	if got := p.ResourceTypeNames(); len(got) != 1 || got[0] != "MyOrg::Network::Vpc" {
+		t.Errorf("ResourceTypeNames = %v", got)
+	}
+	rt := p.GetResourceType("MyOrg::Network::Vpc")
+	if rt == nil {
+		t.Fatal("expected MyOrg::Network::Vpc")
+	}
+	if _, ok := rt.Attributes["VpcId"]; !ok {
+		t.Error("expected read-only VpcId to be an attribute")
+	}
+	if p.GetPropertyType("MyOrg::Network::Vpc.Tag") == nil {
+		t.Error("expected MyOrg::Network::Vpc.Tag")
+	}
+	if p.GetResourceType("AWS::S3::Bucket") != nil || p.GetPropertyType("Tag") != nil {
+		t.Error("expected unknown types to be nil")
+	}
+	if !contains(p.PropertyTypeNames(), "MyOrg::Network::Vpc.Flow") {
+		t.Errorf("PropertyTypeNames = %v", p.PropertyTypeNames())
+	}
+
+	s, err := spec.Materialize(p)
+	if err != nil {
+		t.Fatalf("Materialize failed: %v", err)
+	}
+	if s.ProvenanceOf("MyOrg::Network::Vpc").Source != spec.SourceRegistry {
+		t.Errorf("expected registry provenance, got %+v", s.ProvenanceOf("MyOrg::Network::Vpc"))
+	}
+
+	rs, _ := spec.ParseRegistrySchema([]byte(registrySchemaJSON))
+	if _, err := spec.NewRegistryProvider([]*spec.RegistrySchema{rs, rs}); err == nil {
+		t.Error("expected error for duplicate schemas")
+	}
+}
+
+func TestLayeredProvider(t *testing.T) {
+	base := loadTestSpec(t)
+	override := &spec.Spec{
+		ResourceTypes: map[string]spec.ResourceType{
+			"AWS::S3::Bucket": {Documentation: "override"},
+		},
+	}
+	p := spec.NewLayeredProvider(override, registryProvider(t), base)
+
+	if p.Version() != base.ResourceSpecificationVersion {
+		t.Errorf("Version = %q, want %q", p.Version(), base.ResourceSpecificationVersion)
+	}
+	if got := p.GetResourceType("AWS::S3::Bucket").Documentation; got != "override" {
+		t.Errorf("expected the first layer to win, got %q", got)
+	}
+	if p.GetResourceType("MyOrg::Network::Vpc") == nil {
+		t.Error("expected registry layer types")
+	}
+
+	names := p.ResourceTypeNames()
+	if !contains(names, "MyOrg::Network::Vpc") || !contains(names, "AWS::S3::Bucket") {
+		t.Errorf("ResourceTypeNames = %v", names)
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Errorf("expected sorted unique names, got %v", names)
+			break
+		}
+	}
+
+	s, err := spec.Materialize(p)
+	if err != nil {
+		t.Fatalf("Materialize failed: %v", err)
+	}
+	if s.ResourceTypes["AWS::S3::Bucket"].Documentation != "override" || !s.HasPropertyType("MyOrg::Network::Vpc.Tag") {
+		t.Error("expected materialized spec to combine the layers")
+	}
+}
+
+func TestMaterialize(t *testing.T) {
+	s := loadTestSpec(t)
+	if got, _ := spec.Materialize(s); got != s {
+		t.Error("expected a *Spec to be returned as is")
+	}
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	lazy, err := spec.OpenBinary(data)
+	if err != nil {
+		t.Fatalf("OpenBinary failed: %v", err)
+	}
+	decoded, err := spec.Materialize(lazy)
+	if err != nil {
+		t.Fatalf("Materialize failed: %v", err)
+	}
+	if len(decoded.ResourceTypes) != len(s.ResourceTypes) {
+		t.Errorf("got %d resource types, want %d", len(decoded.ResourceTypes), len(s.ResourceTypes))
+	}
+}
+
+func TestRequiredActions_Provider(t *testing.T) {
+	actions, err := spec.RequiredActions(registryProvider(t), "MyOrg::Network::Vpc", spec.OperationCreate)
+	if err != nil {
+		t.Fatalf("RequiredActions failed: %v", err)
+	}
+	if len(actions) != 2 || actions[0] != "ec2:CreateTags" {
+		t.Errorf("got %v", actions)
+	}
+}
@@ -0,0 +1,173 @@
+package spec
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ValueKind is the semantic kind of a string returned by Ref or GetAtt.
+type ValueKind string
+
+const (
+	KindUnknown ValueKind = ""      // no information available
+	KindARN     ValueKind = "ARN"   // an Amazon Resource Name
+	KindID      ValueKind = "ID"    // a service-assigned identifier (vpc-123, sg-123)
+	KindName    ValueKind = "Name"  // a user-visible name
+	KindURL     ValueKind = "URL"   // a URL
+	KindOther   ValueKind = "Other" // some other value (IP address, hostname, port)
+)
+
+// ValueType is the type of the value returned by Ref or GetAtt.
+type ValueType struct {
+	PrimitiveType     string    // String, Integer, Json, ...; empty for lists
+	Type              string    // List for list values
+	PrimitiveItemType string    // item type of lists of primitives
+	ItemType          string    // item type of lists of property types
+	Kind              ValueKind // semantic kind of the value (or of its items)
+}
+
+// IsList returns true if the value is a list.
+func (v *ValueType) IsList() bool {
+	return v.Type == "List"
+}
+
+// String returns the type as "String", "List<String>", "List<Endpoint>" or
+// with its kind, "String (ARN)".
+func (v *ValueType) String() string {
+	s := v.PrimitiveType
+	if v.IsList() {
+		item := v.PrimitiveItemType
+		if item == "" {
+			item = v.ItemType
+		}
+		s = "List<" + item + ">"
+	}
+	if v.Kind != KindUnknown {
+		s += " (" + string(v.Kind) + ")"
+	}
+	return s
+}
+
+//go:embed refs/kinds.json
+var kindsJSON []byte
+
+// valueKindTable is the bundled table of value kinds.
+type valueKindTable struct {
+	Ref    map[string]ValueKind // resource type -> kind returned by Ref
+	GetAtt map[string]ValueKind // "Type.Attribute" -> kind the attribute name does not reveal
+}
+
+var valueKinds = func() valueKindTable {
+	var kinds valueKindTable
+	if err := json.Unmarshal(kindsJSON, &kinds); err != nil {
+		panic(fmt.Sprintf("spec: invalid bundled value kinds: %v", err))
+	}
+	return kinds
+}()
+
+// RefKind returns the kind of value Ref returns for a resource type,
+// according to the bundled table. The table covers about 75 common types
+// and returns KindUnknown for every other type; Spec.RefType derives a kind
+// for those.
+func RefKind(resourceType string) ValueKind {
+	return valueKinds.Ref[resourceType]
+}
+
+// RefType returns the type of { "Ref": LogicalId } for a resource of the given type.
+// Ref on a resource always returns a string. Its kind comes from the bundled
+// table when listed and is otherwise derived from the resource's members,
+// using the last segment of the type name (VPC in AWS::EC2::VPC):
+//   - an "Id" or "<Type>Id" attribute: ID
+//   - a "Name" or "<Type>Name" property: Name
+//   - an "Arn" or "<Type>Arn" attribute: ARN
+//
+// Types matching no rule return their physical ID, reported as KindID.
+func (s *Spec) RefType(resourceType string) (*ValueType, error) {
+	rt := s.GetResourceType(resourceType)
+	if rt == nil {
+		return nil, fmt.Errorf("resource type %s not found", resourceType)
+	}
+	kind := RefKind(resourceType)
+	if kind == KindUnknown {
+		kind = refKind(resourceType, rt)
+	}
+	return &ValueType{PrimitiveType: "String", Kind: kind}, nil
+}
+
+// refKind derives the kind returned by Ref for a type missing from the table.
+func refKind(resourceType string, rt *ResourceType) ValueKind {
+	name := resourceType[strings.LastIndex(resourceType, "::")+2:]
+	switch {
+	case hasMember(rt.Attributes, name, "Id"):
+		return KindID
+	case hasMember(rt.Properties, name, "Name"):
+		return KindName
+	case hasMember(rt.Attributes, name, "Arn"):
+		return KindARN
+	}
+	return KindID
+}
+
+// hasMember reports whether members has a key equal to suffix or to
+// typeName+suffix, ignoring case (VpcId matches VPC).
+func hasMember[V any](members map[string]V, typeName, suffix string) bool {
+	for m := range members {
+		if strings.EqualFold(m, suffix) || strings.EqualFold(m, typeName+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAttType returns the type of { "Fn::GetAtt": [LogicalId, attribute] } for
+// a resource of the given type. Nested attributes use their dotted name,
+// e.g. "Endpoint.Address". The kind comes from the bundled table when
+// listed and is otherwise inferred from the attribute name: "Arn", "Url",
+// "Id" and "Name" suffixes (and their plurals for lists).
+func (s *Spec) GetAttType(resourceType, attribute string) (*ValueType, error) {
+	rt := s.GetResourceType(resourceType)
+	if rt == nil {
+		return nil, fmt.Errorf("resource type %s not found", resourceType)
+	}
+	attr, ok := rt.Attributes[attribute]
+	if !ok {
+		return nil, fmt.Errorf("resource type %s has no attribute %s", resourceType, attribute)
+	}
+
+	v := &ValueType{
+		PrimitiveType:     attr.PrimitiveType,
+		Type:              attr.Type,
+		PrimitiveItemType: attr.PrimitiveItemType,
+		ItemType:          attr.ItemType,
+	}
+	if kind, ok := valueKinds.GetAtt[resourceType+"."+attribute]; ok {
+		v.Kind = kind
+	} else if v.PrimitiveType == "String" || v.PrimitiveItemType == "String" {
+		v.Kind = attributeKind(attribute, v.IsList())
+	}
+	return v, nil
+}
+
+// attributeKind infers the kind of a string attribute from its name.
+func attributeKind(attribute string, list bool) ValueKind {
+	name := attribute
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	if list {
+		name = strings.TrimSuffix(name, "s")
+	}
+	switch {
+	case strings.HasSuffix(name, "Arn") || strings.HasSuffix(name, "ARN"):
+		return KindARN
+	case strings.HasSuffix(name, "Url") || strings.HasSuffix(name, "URL"):
+		return KindURL
+	case strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "ID"):
+		return KindID
+	case strings.HasSuffix(name, "Name"):
+		return KindName
+	}
+	return KindOther
+}
@@ -0,0 +1,145 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+func TestSpec_RefType(t *testing.T) {
+	s := loadSnapshot(t)
+
+	tests := []struct {
+		resourceType string
+		want         spec.ValueKind
+	}{
+		{"AWS::S3::Bucket", spec.KindName},
+		{"AWS::EC2::VPC", spec.KindID},
+		{"AWS::SNS::Topic", spec.KindARN},
+		{"AWS::SQS::Queue", spec.KindURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			v, err := s.RefType(tt.resourceType)
+			if err != nil {
+				t.Fatalf("RefType failed: %v", err)
+			}
+			if v.PrimitiveType != "String" || v.Kind != tt.want {
+				t.Errorf("got %s, want String (%s)", v, tt.want)
+			}
+		})
+	}
+
+	if _, err := s.RefType("AWS::S3::Nope"); err == nil {
+		t.Error("expected error for unknown resource type")
+	}
+	if got := spec.RefKind("MyOrg::Network::Vpc"); got != spec.KindUnknown {
+		t.Errorf("RefKind(unlisted) = %q, want unknown", got)
+	}
+}
+
+func TestSpec_RefType_Derived(t *testing.T) {
+	s := &spec.Spec{
+		ResourceTypes: map[string]spec.ResourceType{
+			"MyOrg::Network::VPC": {
+				Attributes: map[string]spec.Attribute{"VpcId": {PrimitiveType: "String"}, "Arn": {PrimitiveType: "String"}},
+			},
+			"MyOrg::Queue::Pipe": {
+				Properties: map[string]spec.Property{"PipeName": {PrimitiveType: "String"}},
+				Attributes: map[string]spec.Attribute{"Arn": {PrimitiveType: "String"}},
+			},
+			"MyOrg::Queue::Rule": {
+				Properties: map[string]spec.Property{"Name": {PrimitiveType: "String"}},
+			},
+			"MyOrg::Auth::Key": {
+				Attributes: map[string]spec.Attribute{"KeyArn": {PrimitiveType: "String"}},
+			},
+			"MyOrg::Auth::Grant": {},
+		},
+	}
+
+	tests := []struct {
+		resourceType string
+		want         spec.ValueKind
+	}{
+		{"MyOrg::Network::VPC", spec.KindID},
+		{"MyOrg::Queue::Pipe", spec.KindName},
+		{"MyOrg::Queue::Rule", spec.KindName},
+		{"MyOrg::Auth::Key", spec.KindARN},
+		{"MyOrg::Auth::Grant", spec.KindID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resourceType, func(t *testing.T) {
+			v, err := s.RefType(tt.resourceType)
+			if err != nil {
+				t.Fatalf("RefType failed: %v", err)
+			}
+			if v.PrimitiveType != "String" || v.Kind != tt.want {
+				t.Errorf("got %s, want String (%s)", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpec_GetAttType(t *testing.T) {
+	s := loadSnapshot(t)
+
+	tests := []struct {
+		name         string
+		resourceType string
+		attribute    string
+		want         string
+	}{
+		{"arn", "AWS::S3::Bucket", "Arn", "String (ARN)"},
+		{"prefixed_arn", "AWS::SNS::Topic", "TopicArn", "String (ARN)"},
+		{"url", "AWS::S3::Bucket", "WebsiteURL", "String (URL)"},
+		{"id", "AWS::EC2::VPC", "VpcId", "String (ID)"},
+		{"name", "AWS::SQS::Queue", "QueueName", "String (Name)"},
+		{"table_override", "AWS::EC2::VPC", "DefaultSecurityGroup", "String (ID)"},
+		{"hostname", "AWS::S3::Bucket", "DomainName", "String (Other)"},
+		{"nested", "AWS::RDS::DBInstance", "Endpoint.Address", "String (Other)"},
+		{"nested_override", "AWS::RDS::DBInstance", "Endpoint.HostedZoneId", "String (ID)"},
+		{"list", "AWS::EC2::VPC", "Ipv6CidrBlocks", "List<String> (Other)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := s.GetAttType(tt.resourceType, tt.attribute)
+			if err != nil {
+				t.Fatalf("GetAttType failed: %v", err)
+			}
+			if got := v.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := s.GetAttType("AWS::S3::Bucket", "Nope"); err == nil {
+		t.Error("expected error for unknown attribute")
+	}
+	if _, err := s.GetAttType("AWS::S3::Nope", "Arn"); err == nil {
+		t.Error("expected error for unknown resource type")
+	}
+}
+
+func TestSpec_GetAttType_ItemType(t *testing.T) {
+	s := &spec.Spec{
+		ResourceTypes: map[string]spec.ResourceType{
+			"AWS::Test::Thing": {
+				Attributes: map[string]spec.Attribute{
+					"Endpoints": {Type: "List", ItemType: "Endpoint"},
+				},
+			},
+		},
+	}
+
+	v, err := s.GetAttType("AWS::Test::Thing", "Endpoints")
+	if err != nil {
+		t.Fatalf("GetAttType failed: %v", err)
+	}
+	if v.ItemType != "Endpoint" {
+		t.Errorf("ItemType = %q, want Endpoint", v.ItemType)
+	}
+	if got := v.String(); got != "List<Endpoint>" {
+		t.Errorf("got %s, want List<Endpoint>", got)
+	}
+}
@@ -0,0 +1,117 @@
+package spec
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MultiRegionSpec holds one spec per region and answers availability
+// questions across them.
+//
+// Example:
+//
+//	m, err := spec.FetchMultiRegionSpec([]string{"us-east-1", "ap-southeast-4"}, nil)
+//	missing := m.RegionsWithoutResourceType("AWS::Lambda::Function")
+type MultiRegionSpec struct {
+	Specs map[string]*Spec
+}
+
+// NewMultiRegionSpec creates a MultiRegionSpec from specs keyed by region.
+func NewMultiRegionSpec(specs map[string]*Spec) *MultiRegionSpec {
+	if specs == nil {
+		specs = make(map[string]*Spec)
+	}
+	return &MultiRegionSpec{Specs: specs}
+}
+
+// FetchMultiRegionSpec fetches the spec for each region.
+// The options are copied per region; Region and URL are set for each fetch.
+// If opts is nil, default options are used.
+func FetchMultiRegionSpec(regions []string, opts *FetchOptions) (*MultiRegionSpec, error) {
+	m := NewMultiRegionSpec(nil)
+	for _, region := range regions {
+		regionOpts := FetchOptions{}
+		if opts != nil {
+			regionOpts = *opts
+		}
+		regionOpts.Region = region
+		regionOpts.URL = ""
+
+		s, err := FetchSpec(&regionOpts)
+		if err != nil {
+			return nil, fmt.Errorf("fetching spec for %s: %w", region, err)
+		}
+		m.Specs[region] = s
+	}
+	return m, nil
+}
+
+// Regions returns the regions in the set, sorted.
+func (m *MultiRegionSpec) Regions() []string {
+	regions := make([]string, 0, len(m.Specs))
+	for region := range m.Specs {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// Spec returns the spec for the given region.
+// Returns nil if the region is not in the set.
+func (m *MultiRegionSpec) Spec(region string) *Spec {
+	return m.Specs[region]
+}
+
+// HasResourceType returns true if the resource type is available in the region.
+func (m *MultiRegionSpec) HasResourceType(region, typeName string) bool {
+	s := m.Specs[region]
+	return s != nil && s.HasResourceType(typeName)
+}
+
+// HasProperty returns true if the property is available in the region.
+// typeName may be a resource type or a property type name.
+func (m *MultiRegionSpec) HasProperty(region, typeName, propName string) bool {
+	s := m.Specs[region]
+	if s == nil {
+		return false
+	}
+	if rt := s.GetResourceType(typeName); rt != nil {
+		return rt.HasProperty(propName)
+	}
+	if pt := s.GetPropertyType(typeName); pt != nil {
+		return pt.HasProperty(propName)
+	}
+	return false
+}
+
+// RegionsWithResourceType returns the sorted regions where the resource type is available.
+func (m *MultiRegionSpec) RegionsWithResourceType(typeName string) []string {
+	return m.filterRegions(func(region string) bool {
+		return m.HasResourceType(region, typeName)
+	})
+}
+
+// RegionsWithoutResourceType returns the sorted regions where the resource type is missing.
+func (m *MultiRegionSpec) RegionsWithoutResourceType(typeName string) []string {
+	return m.filterRegions(func(region string) bool {
+		return !m.HasResourceType(region, typeName)
+	})
+}
+
+// RegionsWithoutProperty returns the sorted regions where the property is missing.
+// A region that lacks the owning type also lacks the property.
+func (m *MultiRegionSpec) RegionsWithoutProperty(typeName, propName string) []string {
+	return m.filterRegions(func(region string) bool {
+		return !m.HasProperty(region, typeName, propName)
+	})
+}
+
+func (m *MultiRegionSpec) filterRegions(keep func(region string) bool) []string {
+	var result []string
+	for _, region := range m.Regions() {
+		if keep(region) {
+			result = append(result, region)
+		}
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package spec_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+func TestSpecURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		region  string
+		version string
+		want    string
+	}{
+		{"default", "", "", spec.DefaultSpecURL},
+		{"us_west_2", "us-west-2", "", "https://d201a2mn26r7lk.cloudfront.net/latest/gzip/CloudFormationResourceSpecification.json"},
+		{"versioned", "us-east-1", "150.0.0", "https://d1uauaxba7bl26.cloudfront.net/150.0.0/gzip/CloudFormationResourceSpecification.json"},
+		{"s3_fallback", "ap-southeast-4", "", "https://cfn-resource-specifications-ap-southeast-4-prod.s3.ap-southeast-4.amazonaws.com/latest/gzip/CloudFormationResourceSpecification.json"},
+		{"china", "cn-north-1", "", "https://cfn-resource-specifications-cn-north-1-prod.s3.cn-north-1.amazonaws.com.cn/latest/gzip/CloudFormationResourceSpecification.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spec.SpecURL(tt.region, tt.version); got != tt.want {
+				t.Errorf("SpecURL(%q, %q) = %s, want %s", tt.region, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchSpec_CachePerRegion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testSpecJSON))
+	}))
+	cacheDir := t.TempDir()
+
+	opts := &spec.FetchOptions{URL: server.URL, Region: "us-east-1", CacheDir: cacheDir, Quiet: true}
+	if _, err := spec.FetchSpec(opts); err != nil {
+		t.Fatalf("FetchSpec failed: %v", err)
+	}
+	server.Close()
+
+	// Same region and version is served from the cache.
+	opts = &spec.FetchOptions{URL: server.URL, Region: "us-east-1", CacheDir: cacheDir, Quiet: true}
+	s, err := spec.FetchSpec(opts)
+	if err != nil {
+		t.Fatalf("expected cached spec, got error: %v", err)
+	}
+	if !s.HasResourceType("AWS::S3::Bucket") {
+		t.Error("expected cached spec to contain AWS::S3::Bucket")
+	}
+
+	// Another region or version does not share the cache entry.
+	for _, o := range []*spec.FetchOptions{
+		{URL: server.URL, Region: "eu-west-1", CacheDir: cacheDir, Quiet: true},
+		{URL: server.URL, Region: "us-east-1", Version: "1.0.0", CacheDir: cacheDir, Quiet: true},
+	} {
+		if _, err := spec.FetchSpec(o); err == nil {
+			t.Errorf("expected download error for region %s version %s", o.Region, o.Version)
+		}
+	}
+}
+
+func TestMultiRegionSpec(t *testing.T) {
+	full := loadTestSpec(t)
+	partial := loadTestSpec(t)
+	delete(partial.ResourceTypes, "AWS::EC2::Instance")
+	bucket := partial.ResourceTypes["AWS::S3::Bucket"]
+	delete(bucket.Properties, "CorsConfiguration")
+	partial.ResourceTypes["AWS::S3::Bucket"] = bucket
+
+	m := spec.NewMultiRegionSpec(map[string]*spec.Spec{
+		"us-east-1":      full,
+		"eu-west-1":      full,
+		"ap-southeast-4": partial,
+	})
+
+	if got, want := m.Regions(), []string{"ap-southeast-4", "eu-west-1", "us-east-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Regions() = %v, want %v", got, want)
+	}
+	if m.HasResourceType("ap-southeast-4", "AWS::EC2::Instance") {
+		t.Error("expected AWS::EC2::Instance to be unavailable in ap-southeast-4")
+	}
+	if !m.HasResourceType("us-east-1", "AWS::EC2::Instance") {
+		t.Error("expected AWS::EC2::Instance to be available in us-east-1")
+	}
+	if m.HasResourceType("us-west-2", "AWS::S3::Bucket") {
+		t.Error("expected unknown region to report no resource types")
+	}
+	if got, want := m.RegionsWithResourceType("AWS::EC2::Instance"), []string{"eu-west-1", "us-east-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RegionsWithResourceType() = %v, want %v", got, want)
+	}
+	if got, want := m.RegionsWithoutResourceType("AWS::EC2::Instance"), []string{"ap-southeast-4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RegionsWithoutResourceType() = %v, want %v", got, want)
+	}
+	if got, want := m.RegionsWithoutProperty("AWS::S3::Bucket", "CorsConfiguration"), []string{"ap-southeast-4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RegionsWithoutProperty() = %v, want %v", got, want)
+	}
+	if !m.HasProperty("ap-southeast-4", "AWS::S3::Bucket.Tag", "Key") {
+		t.Error("expected property type properties to be found")
+	}
+}
@@ -0,0 +1,687 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Source identifies where a type definition came from.
+type Source string
+
+const (
+	// SourceAWS is a type published by AWS, either in the resource
+	// specification or as an AWS registry schema.
+	SourceAWS Source = "AWS"
+	// SourceRegistry is a third-party or private registry resource type.
+	SourceRegistry Source = "Registry"
+	// SourceModule is a registry module (type names ending in "::MODULE").
+	SourceModule Source = "Module"
+)
+
+// Provenance records where a type in a spec came from.
+type Provenance struct {
+	Source Source `json:"Source"`
+	Origin string `json:"Origin,omitempty"` // file the type was loaded from, if any
+}
+
+// ProvenanceOf returns the provenance of a resource or property type.
+// Types without a recorded provenance come from the AWS resource specification.
+func (s *Spec) ProvenanceOf(typeName string) Provenance {
+	if p, ok := s.Provenance[typeName]; ok {
+		return p
+	}
+	return Provenance{Source: SourceAWS}
+}
+
+// IsAWSNative returns true if the type was published by AWS.
+func (s *Spec) IsAWSNative(typeName string) bool {
+	return s.ProvenanceOf(typeName).Source == SourceAWS
+}
+
+// RegistrySchema is a CloudFormation registry resource or module schema,
+// as returned by DescribeType or published by `cfn submit`.
+type RegistrySchema struct {
+	TypeName             string                     `json:"typeName"`
+	Description          string                     `json:"description"`
+	DocumentationURL     string                     `json:"documentationUrl"`
+	SourceURL            string                     `json:"sourceUrl"`
+	Definitions          map[string]*SchemaNode     `json:"definitions"`
+	Properties           map[string]*SchemaNode     `json:"properties"`
+	Required             []string                   `json:"required"`
+	ReadOnlyProperties   []string                   `json:"readOnlyProperties"`
+	CreateOnlyProperties []string                   `json:"createOnlyProperties"`
+	WriteOnlyProperties  []string                   `json:"writeOnlyProperties"`
+	PrimaryIdentifier    []string                   `json:"primaryIdentifier"`
+	Handlers             map[string]RegistryHandler `json:"handlers"`
+
+	// Origin is the file the schema was loaded from, if any.
+	Origin string `json:"-"`
+}
+
+// RegistryHandler is a resource handler declaration in a registry schema.
+type RegistryHandler struct {
+	Permissions      []string `json:"permissions"`
+	TimeoutInMinutes int      `json:"timeoutInMinutes,omitempty"`
+}
+
+// SchemaNode is the subset of JSON Schema used by registry schemas.
+type SchemaNode struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 any                    `json:"type"` // string or []string
+	Description          string                 `json:"description"`
+	Properties           map[string]*SchemaNode `json:"properties"`
+	PatternProperties    map[string]*SchemaNode `json:"patternProperties"`
+	AdditionalProperties any                    `json:"additionalProperties"` // bool or schema
+	Items                *SchemaNode            `json:"items"`
+	Required             []string               `json:"required"`
+	UniqueItems          bool                   `json:"uniqueItems"`
+	Enum                 []any                  `json:"enum"`
+}
+
+// IsModule returns true if the schema describes a registry module.
+func (rs *RegistrySchema) IsModule() bool {
+	return strings.HasSuffix(rs.TypeName, "::MODULE")
+}
+
+// Source returns the provenance source of the schema's type.
+func (rs *RegistrySchema) Source() Source {
+	switch {
+	case rs.IsModule():
+		return SourceModule
+	case strings.HasPrefix(rs.TypeName, "AWS::"):
+		return SourceAWS
+	default:
+		return SourceRegistry
+	}
+}
+
+// ParseRegistrySchema parses a registry schema document.
+func ParseRegistrySchema(data []byte) (*RegistrySchema, error) {
+	var rs RegistrySchema
+	if err := json.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("parsing registry schema: %w", err)
+	}
+	if rs.TypeName == "" {
+		return nil, fmt.Errorf("parsing registry schema: missing typeName")
+	}
+	return &rs, nil
+}
+
+// LoadRegistrySchema loads a registry schema from a JSON file.
+func LoadRegistrySchema(path string) (*RegistrySchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading registry schema: %w", err)
+	}
+	rs, err := ParseRegistrySchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	rs.Origin = path
+	return rs, nil
+}
+
+// LoadRegistrySchemas loads every registry schema (*.json) in a directory, in name order.
+func LoadRegistrySchemas(dir string) ([]*RegistrySchema, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	schemas := make([]*RegistrySchema, 0, len(paths))
+	for _, path := range paths {
+		rs, err := LoadRegistrySchema(path)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, rs)
+	}
+	return schemas, nil
+}
+
+// ToSpec converts the registry schema to a spec containing its resource
+// type and the property types for its object definitions.
+//
+// Read-only properties become attributes, nested ones such as
+// "/properties/Endpoint/Address" as "Endpoint.Address", and are left out of
+// the properties and property types; a property whose sub-properties are
+// all read-only is left out entirely. Create-only properties are Immutable
+// and the rest Mutable. Definitions
+// become property types named "TypeName.Definition"; inline objects are
+// named after their property. Objects without properties and values with
+// several types become Json.
+func (rs *RegistrySchema) ToSpec() (*Spec, error) {
+	c := &schemaConverter{
+		schema:     rs,
+		spec:       &Spec{ResourceTypes: map[string]ResourceType{}, PropertyTypes: map[string]PropertyType{}, Provenance: map[string]Provenance{}},
+		readOnly:   stringSet(rs.ReadOnlyProperties),
+		createOnly: stringSet(rs.CreateOnlyProperties),
+		typeNames:  map[string]string{},
+		taken:      map[string]bool{},
+	}
+
+	properties := rs.Properties
+	if rs.IsModule() {
+		properties = moduleParameters(rs)
+	}
+
+	rt := ResourceType{
+		Documentation: rs.DocumentationURL,
+		Properties:    map[string]Property{},
+		Attributes:    map[string]Attribute{},
+		Handlers:      rs.handlers(),
+	}
+	if rt.Documentation == "" {
+		rt.Documentation = rs.Description
+	}
+
+	required := stringSet(rs.Required)
+	for _, name := range sortedKeys(properties) {
+		node := properties[name]
+		pointer := "/properties/" + name
+		if c.readOnly[pointer] {
+			c.addAttributes(&rt, name, pointer, node)
+			continue
+		}
+		c.addNestedAttributes(&rt, name, pointer, node)
+		if !c.writable(pointer, node) {
+			continue
+		}
+		prop, err := c.property(name, pointer, node)
+		if err != nil {
+			return nil, fmt.Errorf("%s: property %s: %w", rs.TypeName, name, err)
+		}
+		prop.Required = required[name]
+		prop.UpdateType = "Mutable"
+		if c.createOnly[pointer] {
+			prop.UpdateType = "Immutable"
+		}
+		rt.Properties[name] = prop
+	}
+
+	c.spec.ResourceTypes[rs.TypeName] = rt
+	provenance := Provenance{Source: rs.Source(), Origin: rs.Origin}
+	for name := range c.spec.ResourceTypes {
+		c.spec.Provenance[name] = provenance
+	}
+	for name := range c.spec.PropertyTypes {
+		c.spec.Provenance[name] = provenance
+	}
+	return c.spec, nil
+}
+
+type schemaConverter struct {
+	schema     *RegistrySchema
+	spec       *Spec
+	readOnly   map[string]bool
+	createOnly map[string]bool
+	typeNames  map[string]string // property type key to its name
+	taken      map[string]bool   // property type names in use
+}
+
+// definitionPrefix is the prefix of references to schema definitions.
+const definitionPrefix = "#/definitions/"
+
+// addAttributes records a read-only property as an attribute. Objects with
+// read-only sub-properties produce "Name.Sub" attributes.
+func (c *schemaConverter) addAttributes(rt *ResourceType, name, pointer string, node *SchemaNode) {
+	node = c.deref(node)
+	if node != nil && len(node.Properties) > 0 {
+		for _, sub := range sortedKeys(node.Properties) {
+			c.addAttributes(rt, name+"."+sub, pointer+"/"+sub, node.Properties[sub])
+		}
+		return
+	}
+	attr := Attribute{PrimitiveType: "Json"}
+	if prop, err := c.property(name, pointer, node); err == nil && (prop.PrimitiveType != "" || prop.PrimitiveItemType != "") {
+		attr = Attribute{
+			PrimitiveType:     prop.PrimitiveType,
+			Type:              prop.Type,
+			PrimitiveItemType: prop.PrimitiveItemType,
+		}
+	}
+	rt.Attributes[name] = attr
+}
+
+// addNestedAttributes records the read-only sub-properties of a writable
+// property, such as "/properties/Endpoint/Address", as attributes named
+// "Endpoint.Address". Pointers into list items have no attribute.
+func (c *schemaConverter) addNestedAttributes(rt *ResourceType, name, pointer string, node *SchemaNode) {
+	for _, ro := range sortedKeys(c.readOnly) {
+		rel, ok := strings.CutPrefix(ro, pointer+"/")
+		if !ok || strings.Contains("/"+rel+"/", "/*/") || c.readOnlyAncestor(pointer, ro) {
+			continue
+		}
+		tokens := strings.Split(rel, "/")
+		sub := node
+		for _, t := range tokens {
+			if sub = c.deref(sub); sub != nil {
+				sub = sub.Properties[t]
+			}
+		}
+		c.addAttributes(rt, name+"."+strings.Join(tokens, "."), ro, sub)
+	}
+}
+
+// readOnlyAncestor reports whether a pointer between base and ro, such as
+// "/properties/A/B" for "/properties/A/B/C", is itself read-only, in which
+// case ro is covered by that pointer's attributes.
+func (c *schemaConverter) readOnlyAncestor(base, ro string) bool {
+	for p := ro; len(p) > len(base); {
+		i := strings.LastIndex(p, "/")
+		if i < 0 {
+			return false
+		}
+		p = p[:i]
+		if len(p) > len(base) && c.readOnly[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// writable reports whether the value at pointer has anything that is not
+// read-only: false if it is read-only or all of its sub-properties are.
+func (c *schemaConverter) writable(pointer string, node *SchemaNode) bool {
+	if c.readOnly[pointer] {
+		return false
+	}
+	if !c.hasReadOnlyBelow(pointer) {
+		return true
+	}
+	node = c.deref(node)
+	switch {
+	case node == nil:
+		return true
+	case schemaType(node) == "array":
+		return c.writable(pointer+"/*", node.Items)
+	case len(node.Properties) > 0:
+		for _, sub := range sortedKeys(node.Properties) {
+			if c.writable(pointer+"/"+sub, node.Properties[sub]) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (c *schemaConverter) hasReadOnlyBelow(pointer string) bool {
+	for ro := range c.readOnly {
+		if strings.HasPrefix(ro, pointer+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// property converts the schema node at pointer to a Property.
+func (c *schemaConverter) property(name, pointer string, node *SchemaNode) (Property, error) {
+	if node == nil {
+		return Property{PrimitiveType: "Json"}, nil
+	}
+	prop := Property{Documentation: node.Description}
+
+	if node.Ref != "" {
+		def, typeName, err := c.definition(node.Ref)
+		if err != nil {
+			return prop, err
+		}
+		if !isObjectWithProperties(def) {
+			inner, err := c.property(name, pointer, def)
+			if err != nil {
+				return prop, err
+			}
+			inner.Documentation = prop.Documentation
+			return inner, nil
+		}
+		typeName, err = c.propertyType(typeName, pointer, def)
+		if err != nil {
+			return prop, err
+		}
+		prop.Type = typeName
+		return prop, nil
+	}
+
+	switch schemaType(node) {
+	case "string":
+		prop.PrimitiveType = "String"
+	case "integer":
+		prop.PrimitiveType = "Integer"
+	case "number":
+		prop.PrimitiveType = "Double"
+	case "boolean":
+		prop.PrimitiveType = "Boolean"
+	case "array":
+		item, err := c.property(name, pointer+"/*", node.Items)
+		if err != nil {
+			return prop, err
+		}
+		prop.Type = "List"
+		prop.DuplicatesAllowed = !node.UniqueItems
+		setItemType(&prop, item)
+	case "object":
+		switch {
+		case len(node.Properties) > 0:
+			typeName, err := c.propertyType(name, pointer, node)
+			if err != nil {
+				return prop, err
+			}
+			prop.Type = typeName
+		case mapValueSchema(node) != nil:
+			item, err := c.property(name, pointer+"/*", mapValueSchema(node))
+			if err != nil {
+				return prop, err
+			}
+			prop.Type = "Map"
+			setItemType(&prop, item)
+		default:
+			prop.PrimitiveType = "Json"
+		}
+	default:
+		prop.PrimitiveType = "Json"
+	}
+	return prop, nil
+}
+
+// propertyType converts the object schema at pointer to a property type,
+// without its read-only sub-properties, and returns the type's name.
+//
+// The name is typeName unless a different schema already has it, as with
+// two inline "Config" objects under different parents; the type is then
+// named after its path, such as "SourceConfig", with a number appended if
+// that is taken too.
+func (c *schemaConverter) propertyType(typeName, pointer string, node *SchemaNode) (string, error) {
+	key := c.typeKey(typeName, pointer, node)
+	if name, ok := c.typeNames[key]; ok {
+		return name, nil
+	}
+	typeName = c.uniqueTypeName(typeName, pointer, node)
+	c.typeNames[key] = typeName
+	c.taken[typeName] = true
+	fullName := GetPropertyTypeForResource(c.schema.TypeName, typeName)
+
+	pt := PropertyType{Documentation: node.Description, Properties: map[string]Property{}}
+	required := stringSet(node.Required)
+	for _, name := range sortedKeys(node.Properties) {
+		sub := pointer + "/" + name
+		if !c.writable(sub, node.Properties[name]) {
+			continue
+		}
+		prop, err := c.property(name, sub, node.Properties[name])
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+		prop.Required = required[name]
+		prop.UpdateType = "Mutable"
+		pt.Properties[name] = prop
+	}
+	c.spec.PropertyTypes[fullName] = pt
+	return typeName, nil
+}
+
+// typeKey identifies a property type by its name, its schema and the
+// read-only pointers below it, which decide the properties it keeps.
+func (c *schemaConverter) typeKey(typeName, pointer string, node *SchemaNode) string {
+	var b strings.Builder
+	b.WriteString(typeName)
+	if data, err := json.Marshal(node); err == nil {
+		b.WriteByte(0)
+		b.Write(data)
+	}
+	for _, ro := range sortedKeys(c.readOnly) {
+		if rel, ok := strings.CutPrefix(ro, pointer+"/"); ok {
+			b.WriteByte(0)
+			b.WriteString(rel)
+		}
+	}
+	return b.String()
+}
+
+// uniqueTypeName returns a free property type name for the schema at
+// pointer. Inline objects leave the names of definitions to them.
+func (c *schemaConverter) uniqueTypeName(typeName, pointer string, node *SchemaNode) string {
+	def, isDefinition := c.schema.Definitions[typeName]
+	if !c.taken[typeName] && (def == node || !isDefinition) {
+		return typeName
+	}
+	var path strings.Builder
+	for _, segment := range strings.Split(pointer, "/") {
+		if segment != "" && segment != "properties" && segment != "*" {
+			path.WriteString(segment)
+		}
+	}
+	name := path.String()
+	if name == "" || !strings.HasSuffix(name, typeName) {
+		name += typeName
+	}
+	if _, isDefinition := c.schema.Definitions[name]; !c.taken[name] && !isDefinition {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s%d", name, i)
+		if _, isDefinition := c.schema.Definitions[candidate]; !c.taken[candidate] && !isDefinition {
+			return candidate
+		}
+	}
+}
+
+// definition resolves a "#/definitions/Name" reference.
+func (c *schemaConverter) definition(ref string) (*SchemaNode, string, error) {
+	if !strings.HasPrefix(ref, definitionPrefix) {
+		return nil, "", fmt.Errorf("unsupported $ref %q", ref)
+	}
+	name := strings.TrimPrefix(ref, definitionPrefix)
+	def, ok := c.schema.Definitions[name]
+	if !ok {
+		return nil, "", fmt.Errorf("definition %q not found", name)
+	}
+	return def, name, nil
+}
+
+// deref follows a definition reference, returning the node itself otherwise.
+func (c *schemaConverter) deref(node *SchemaNode) *SchemaNode {
+	if node == nil || node.Ref == "" {
+		return node
+	}
+	if def, _, err := c.definition(node.Ref); err == nil {
+		return def
+	}
+	return node
+}
+
+// setItemType sets the list or map item type from a converted item property.
+func setItemType(prop *Property, item Property) {
+	switch {
+	case item.PrimitiveType != "":
+		prop.PrimitiveItemType = item.PrimitiveType
+	case item.Type == "List" || item.Type == "Map":
+		// Nested collections have no legacy representation.
+		prop.Type = ""
+		prop.PrimitiveType = "Json"
+	default:
+		prop.ItemType = item.Type
+	}
+}
+
+// moduleParameters returns the parameters of a module schema as string properties.
+func moduleParameters(rs *RegistrySchema) map[string]*SchemaNode {
+	params := map[string]*SchemaNode{}
+	if p, ok := rs.Properties["Parameters"]; ok && p != nil {
+		for name, param := range p.Properties {
+			node := &SchemaNode{Type: "string"}
+			if param != nil {
+				node.Description = param.Description
+			}
+			params[name] = node
+		}
+	}
+	return params
+}
+
+// schemaType returns the JSON Schema type, or "" if there are several.
+func schemaType(node *SchemaNode) string {
+	switch t := node.Type.(type) {
+	case string:
+		return t
+	case []any:
+		if len(t) == 1 {
+			if s, ok := t[0].(string); ok {
+				return s
+			}
+		}
+		return ""
+	case nil:
+		if len(node.Properties) > 0 {
+			return "object"
+		}
+	}
+	return ""
+}
+
+func isObjectWithProperties(node *SchemaNode) bool {
+	return node != nil && len(node.Properties) > 0 && (schemaType(node) == "object" || node.Type == nil)
+}
+
+// mapValueSchema returns the schema of map values for objects declared with
+// patternProperties or an additionalProperties schema.
+func mapValueSchema(node *SchemaNode) *SchemaNode {
+	if len(node.PatternProperties) == 1 {
+		for _, v := range node.PatternProperties {
+			return v
+		}
+	}
+	if m, ok := node.AdditionalProperties.(map[string]any); ok {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return nil
+		}
+		var sub SchemaNode
+		if err := json.Unmarshal(data, &sub); err != nil {
+			return nil
+		}
+		return &sub
+	}
+	return nil
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// ConflictPolicy decides what happens when a merged type already exists.
+type ConflictPolicy int
+
+const (
+	// ConflictError fails the merge without changing the spec.
+	ConflictError ConflictPolicy = iota
+	// ConflictKeepExisting keeps the existing definition.
+	ConflictKeepExisting
+	// ConflictReplace replaces the existing definition.
+	ConflictReplace
+)
+
+// MergeOptions configures Merge.
+type MergeOptions struct {
+	// Conflict decides how types present in both specs are handled.
+	// Defaults to ConflictError.
+	Conflict ConflictPolicy
+}
+
+// MergeConflictError lists the types that exist in both specs.
+type MergeConflictError struct {
+	Types []string
+}
+
+// Error implements the error interface.
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("types already defined: %s", strings.Join(e.Types, ", "))
+}
+
+// Merge adds the resource and property types of other to the spec,
+// together with their provenance. Types without recorded provenance in
+// other are treated as AWS-native. If opts is nil, conflicts are errors.
+func (s *Spec) Merge(other *Spec, opts *MergeOptions) error {
+	if opts == nil {
+		opts = &MergeOptions{}
+	}
+
+	var conflicts []string
+	for name := range other.ResourceTypes {
+		if s.HasResourceType(name) {
+			conflicts = append(conflicts, name)
+		}
+	}
+	for name := range other.PropertyTypes {
+		if s.HasPropertyType(name) {
+			conflicts = append(conflicts, name)
+		}
+	}
+	sort.Strings(conflicts)
+	if len(conflicts) > 0 && opts.Conflict == ConflictError {
+		return &MergeConflictError{Types: conflicts}
+	}
+
+	if s.ResourceTypes == nil {
+		s.ResourceTypes = make(map[string]ResourceType)
+	}
+	if s.PropertyTypes == nil {
+		s.PropertyTypes = make(map[string]PropertyType)
+	}
+
+	keep := func(exists bool) bool {
+		return exists && opts.Conflict == ConflictKeepExisting
+	}
+	for name, rt := range other.ResourceTypes {
+		if keep(s.HasResourceType(name)) {
+			continue
+		}
+		s.ResourceTypes[name] = rt
+		s.setProvenance(name, other.ProvenanceOf(name))
+	}
+	for name, pt := range other.PropertyTypes {
+		if keep(s.HasPropertyType(name)) {
+			continue
+		}
+		s.PropertyTypes[name] = pt
+		s.setProvenance(name, other.ProvenanceOf(name))
+	}
+	return nil
+}
+
+// MergeRegistrySchemas converts registry schemas and merges them into the spec.
+// All schemas are converted before any is merged, so a conversion error
+// leaves the spec unchanged.
+func (s *Spec) MergeRegistrySchemas(schemas []*RegistrySchema, opts *MergeOptions) error {
+	combined := &Spec{ResourceTypes: map[string]ResourceType{}, PropertyTypes: map[string]PropertyType{}}
+	for _, rs := range schemas {
+		converted, err := rs.ToSpec()
+		if err != nil {
+			return err
+		}
+		if err := combined.Merge(converted, nil); err != nil {
+			return fmt.Errorf("%s: %w", rs.TypeName, err)
+		}
+	}
+	return s.Merge(combined, opts)
+}
+
+func (s *Spec) setProvenance(typeName string, p Provenance) {
+	if p.Source == SourceAWS && p.Origin == "" {
+		delete(s.Provenance, typeName)
+		return
+	}
+	if s.Provenance == nil {
+		s.Provenance = make(map[string]Provenance)
+	}
+	s.Provenance[typeName] = p
+}
@@ -0,0 +1,358 @@
+package spec_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+const registrySchemaJSON = `{
+  "typeName": "MyOrg::Network::Vpc",
+  "description": "A private VPC resource",
+  "documentationUrl": "https://example.com/docs/vpc",
+  "definitions": {
+    "Tag": {
+      "type": "object",
+      "properties": {
+        "Key": {"type": "string"},
+        "Value": {"type": "string"}
+      },
+      "required": ["Key", "Value"]
+    },
+    "Cidr": {"type": "string"}
+  },
+  "properties": {
+    "CidrBlock": {"$ref": "#/definitions/Cidr"},
+    "Name": {"type": "string"},
+    "MaxAzs": {"type": "integer"},
+    "Labels": {"type": "object", "patternProperties": {".*": {"type": "string"}}},
+    "Settings": {"type": "object"},
+    "Tags": {"type": "array", "uniqueItems": true, "items": {"$ref": "#/definitions/Tag"}},
+    "Flow": {"type": "object", "properties": {"Enabled": {"type": "boolean"}}},
+    "VpcId": {"type": "string"},
+    "Endpoint": {"type": "object", "properties": {"Address": {"type": "string"}, "Port": {"type": "integer"}}}
+  },
+  "required": ["CidrBlock"],
+  "readOnlyProperties": ["/properties/VpcId", "/properties/Endpoint"],
+  "createOnlyProperties": ["/properties/CidrBlock"],
+  "primaryIdentifier": ["/properties/VpcId"],
+  "handlers": {
+    "create": {"permissions": ["ec2:CreateVpc", "ec2:CreateTags"]},
+    "delete": {"permissions": ["ec2:DeleteVpc"]}
+  }
+}`
+
+const registryModuleJSON = `{
+  "typeName": "MyOrg::Storage::Bucket::MODULE",
+  "description": "Bucket module",
+  "properties": {
+    "Parameters": {
+      "type": "object",
+      "properties": {
+        "BucketName": {"type": "object", "description": "Name of the bucket"}
+      }
+    },
+    "Resources": {"type": "object"}
+  }
+}`
+
+func TestRegistrySchema_ToSpec(t *testing.T) {
+	rs, err := spec.ParseRegistrySchema([]byte(registrySchemaJSON))
+	if err != nil {
+		t.Fatalf("ParseRegistrySchema failed: %v", err)
+	}
+	s, err := rs.ToSpec()
+	if err != nil {
+		t.Fatalf("ToSpec failed: %v", err)
+	}
+
+	rt := s.GetResourceType("MyOrg::Network::Vpc")
+	if rt == nil {
+		t.Fatal("expected resource type in converted spec")
+	}
+	if rt.Documentation != "https://example.com/docs/vpc" {
+		t.Errorf("Documentation = %q", rt.Documentation)
+	}
+
+	tests := []struct {
+		name      string
+		primitive string
+		typ       string
+		itemType  string
+		primItem  string
+		required  bool
+		update    string
+	}{
+		{name: "CidrBlock", primitive: "String", required: true, update: "Immutable"},
+		{name: "Name", primitive: "String", update: "Mutable"},
+		{name: "MaxAzs", primitive: "Integer", update: "Mutable"},
+		{name: "Labels", typ: "Map", primItem: "String", update: "Mutable"},
+		{name: "Settings", primitive: "Json", update: "Mutable"},
+		{name: "Tags", typ: "List", itemType: "Tag", update: "Mutable"},
+		{name: "Flow", typ: "Flow", update: "Mutable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := rt.Properties[tt.name]
+			if !ok {
+				t.Fatalf("property %s missing", tt.name)
+			}
+			if p.PrimitiveType != tt.primitive || p.Type != tt.typ || p.ItemType != tt.itemType || p.PrimitiveItemType != tt.primItem {
+				t.Errorf("got %+v", p)
+			}
+			if p.Required != tt.required {
+				t.Errorf("Required = %v, want %v", p.Required, tt.required)
+			}
+			if p.UpdateType != tt.update {
+				t.Errorf("UpdateType = %s, want %s", p.UpdateType, tt.update)
+			}
+		})
+	}
+
+	if rt.Properties["Tags"].DuplicatesAllowed {
+		t.Error("expected uniqueItems to disallow duplicates")
+	}
+	if _, ok := rt.Properties["VpcId"]; ok {
+		t.Error("expected read-only VpcId to be an attribute, not a property")
+	}
+	for _, attr := range []string{"VpcId", "Endpoint.Address", "Endpoint.Port"} {
+		if _, ok := rt.Attributes[attr]; !ok {
+			t.Errorf("expected attribute %s, got %v", attr, rt.Attributes)
+		}
+	}
+	if got := rt.Attributes["Endpoint.Port"].PrimitiveType; got != "Integer" {
+		t.Errorf("Endpoint.Port type = %s, want Integer", got)
+	}
+
+	tag := s.GetPropertyType("MyOrg::Network::Vpc.Tag")
+	if tag == nil || !tag.Properties["Key"].Required {
+		t.Errorf("expected Tag property type with required Key, got %+v", tag)
+	}
+	if s.GetPropertyType("MyOrg::Network::Vpc.Flow") == nil {
+		t.Error("expected inline object to become a property type")
+	}
+
+	// The converted type resolves like any other.
+	r, err := s.ResolvePath("MyOrg::Network::Vpc", "Tags[*].Key")
+	if err != nil || r.PrimitiveType != "String" {
+		t.Errorf("ResolvePath(Tags[*].Key) = %+v, %v", r, err)
+	}
+}
+
+func TestRegistrySchema_ToSpec_NestedReadOnly(t *testing.T) {
+	rs, err := spec.ParseRegistrySchema([]byte(`{
+  "typeName": "MyOrg::Db::Cluster",
+  "properties": {
+    "Name": {"type": "string"},
+    "Connection": {"type": "object", "properties": {"Address": {"type": "string"}, "Port": {"type": "integer"}}},
+    "Storage": {"type": "object", "properties": {"Size": {"type": "integer"}, "VolumeId": {"type": "string"}}}
+  },
+  "readOnlyProperties": ["/properties/Connection/Address", "/properties/Connection/Port", "/properties/Storage/VolumeId"]
+}`))
+	if err != nil {
+		t.Fatalf("ParseRegistrySchema failed: %v", err)
+	}
+	s, err := rs.ToSpec()
+	if err != nil {
+		t.Fatalf("ToSpec failed: %v", err)
+	}
+	rt := s.GetResourceType("MyOrg::Db::Cluster")
+
+	tests := []struct {
+		attr      string
+		primitive string
+	}{
+		{"Connection.Address", "String"},
+		{"Connection.Port", "Integer"},
+		{"Storage.VolumeId", "String"},
+	}
+	for _, tt := range tests {
+		if got, ok := rt.Attributes[tt.attr]; !ok || got.PrimitiveType != tt.primitive {
+			t.Errorf("attribute %s = %+v, %v, want %s", tt.attr, got, ok, tt.primitive)
+		}
+	}
+
+	if _, ok := rt.Properties["Connection"]; ok {
+		t.Error("expected fully read-only Connection not to be a property")
+	}
+	if s.GetPropertyType("MyOrg::Db::Cluster.Connection") != nil {
+		t.Error("expected no property type for fully read-only Connection")
+	}
+	storage := s.GetPropertyType("MyOrg::Db::Cluster.Storage")
+	if storage == nil {
+		t.Fatal("expected Storage property type")
+	}
+	if _, ok := storage.Properties["VolumeId"]; ok {
+		t.Error("expected read-only VolumeId to be dropped from the Storage property type")
+	}
+	if _, ok := storage.Properties["Size"]; !ok {
+		t.Error("expected writable Size in the Storage property type")
+	}
+}
+
+func TestRegistrySchema_ToSpec_InlineNameCollision(t *testing.T) {
+	rs, err := spec.ParseRegistrySchema([]byte(`{
+  "typeName": "MyOrg::Data::Pipeline",
+  "properties": {
+    "Config": {"type": "object", "properties": {"Name": {"type": "string"}}},
+    "Source": {"type": "object", "properties": {"Config": {"type": "object", "properties": {"Url": {"type": "string"}}}}},
+    "Target": {"type": "object", "properties": {"Config": {"type": "object", "properties": {"Bucket": {"type": "string"}}}}},
+    "Backup": {"type": "object", "properties": {"Config": {"type": "object", "properties": {"Name": {"type": "string"}}}}}
+  }
+}`))
+	if err != nil {
+		t.Fatalf("ParseRegistrySchema failed: %v", err)
+	}
+	s, err := rs.ToSpec()
+	if err != nil {
+		t.Fatalf("ToSpec failed: %v", err)
+	}
+
+	tests := []struct {
+		parent   string
+		typeName string
+		property string
+	}{
+		{"Backup", "Config", "Name"},
+		{"Source", "SourceConfig", "Url"},
+		{"Target", "TargetConfig", "Bucket"},
+	}
+	for _, tt := range tests {
+		parent := s.GetPropertyType("MyOrg::Data::Pipeline." + tt.parent)
+		if parent == nil {
+			t.Fatalf("property type %s missing", tt.parent)
+		}
+		if got := parent.Properties["Config"].Type; got != tt.typeName {
+			t.Errorf("%s.Config type = %s, want %s", tt.parent, got, tt.typeName)
+		}
+		pt := s.GetPropertyType("MyOrg::Data::Pipeline." + tt.typeName)
+		if pt == nil {
+			t.Fatalf("property type %s missing", tt.typeName)
+		}
+		if _, ok := pt.Properties[tt.property]; !ok || len(pt.Properties) != 1 {
+			t.Errorf("%s properties = %v, want only %s", tt.typeName, pt.Properties, tt.property)
+		}
+	}
+}
+
+func TestRegistrySchema_Module(t *testing.T) {
+	rs, err := spec.ParseRegistrySchema([]byte(registryModuleJSON))
+	if err != nil {
+		t.Fatalf("ParseRegistrySchema failed: %v", err)
+	}
+	if !rs.IsModule() || rs.Source() != spec.SourceModule {
+		t.Errorf("expected module source, got %s", rs.Source())
+	}
+	s, err := rs.ToSpec()
+	if err != nil {
+		t.Fatalf("ToSpec failed: %v", err)
+	}
+	rt := s.GetResourceType("MyOrg::Storage::Bucket::MODULE")
+	if rt == nil {
+		t.Fatal("expected module resource type")
+	}
+	if p, ok := rt.Properties["BucketName"]; !ok || p.PrimitiveType != "String" {
+		t.Errorf("expected BucketName parameter as String property, got %+v", rt.Properties)
+	}
+	if _, ok := rt.Properties["Resources"]; ok {
+		t.Error("expected module Resources not to be a property")
+	}
+}
+
+func TestParseRegistrySchema_Errors(t *testing.T) {
+	if _, err := spec.ParseRegistrySchema([]byte(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+	if _, err := spec.ParseRegistrySchema([]byte(`{"properties": {}}`)); err == nil {
+		t.Error("expected error for missing typeName")
+	}
+
+	rs, _ := spec.ParseRegistrySchema([]byte(`{"typeName": "A::B::C", "properties": {"X": {"$ref": "#/definitions/Missing"}}}`))
+	if _, err := rs.ToSpec(); err == nil {
+		t.Error("expected error for unresolved $ref")
+	}
+}
+
+func TestSpec_MergeRegistrySchemas(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"vpc.json": registrySchemaJSON, "module.json": registryModuleJSON} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	schemas, err := spec.LoadRegistrySchemas(dir)
+	if err != nil {
+		t.Fatalf("LoadRegistrySchemas failed: %v", err)
+	}
+	if len(schemas) != 2 || schemas[0].TypeName != "MyOrg::Storage::Bucket::MODULE" {
+		t.Fatalf("expected schemas in file name order, got %d", len(schemas))
+	}
+
+	s := loadTestSpec(t)
+	if err := s.MergeRegistrySchemas(schemas, nil); err != nil {
+		t.Fatalf("MergeRegistrySchemas failed: %v", err)
+	}
+
+	if !s.HasResourceType("MyOrg::Network::Vpc") || !s.HasPropertyType("MyOrg::Network::Vpc.Tag") {
+		t.Error("expected merged types")
+	}
+	if !s.IsAWSNative("AWS::S3::Bucket") {
+		t.Error("expected existing types to stay AWS-native")
+	}
+	p := s.ProvenanceOf("MyOrg::Network::Vpc")
+	if p.Source != spec.SourceRegistry || p.Origin != filepath.Join(dir, "vpc.json") {
+		t.Errorf("ProvenanceOf = %+v", p)
+	}
+	if s.ProvenanceOf("MyOrg::Network::Vpc.Tag").Source != spec.SourceRegistry {
+		t.Error("expected property types to carry provenance")
+	}
+	if s.ProvenanceOf("MyOrg::Storage::Bucket::MODULE").Source != spec.SourceModule {
+		t.Error("expected module provenance")
+	}
+}
+
+func TestSpec_Merge_Conflicts(t *testing.T) {
+	rs, _ := spec.ParseRegistrySchema([]byte(`{"typeName": "AWS::S3::Bucket", "properties": {"Only": {"type": "string"}}}`))
+	other, err := rs.ToSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("error", func(t *testing.T) {
+		s := loadTestSpec(t)
+		err := s.Merge(other, nil)
+		var conflict *spec.MergeConflictError
+		if !errors.As(err, &conflict) || len(conflict.Types) != 1 || conflict.Types[0] != "AWS::S3::Bucket" {
+			t.Fatalf("expected conflict error, got %v", err)
+		}
+		if _, ok := s.ResourceTypes["AWS::S3::Bucket"].Properties["BucketName"]; !ok {
+			t.Error("expected spec unchanged after conflict")
+		}
+	})
+
+	t.Run("keep_existing", func(t *testing.T) {
+		s := loadTestSpec(t)
+		if err := s.Merge(other, &spec.MergeOptions{Conflict: spec.ConflictKeepExisting}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := s.ResourceTypes["AWS::S3::Bucket"].Properties["Only"]; ok {
+			t.Error("expected existing definition to be kept")
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		s := loadTestSpec(t)
+		if err := s.Merge(other, &spec.MergeOptions{Conflict: spec.ConflictReplace}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := s.ResourceTypes["AWS::S3::Bucket"].Properties["Only"]; !ok {
+			t.Error("expected definition to be replaced")
+		}
+		if !s.IsAWSNative("AWS::S3::Bucket") {
+			t.Error("expected AWS:: registry schema to remain AWS-native")
+		}
+	})
+}
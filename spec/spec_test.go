@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/lex00/cloudformation-schema-go/spec"
+	"github.com/lex00/cloudformation-schema-go/specembed"
 )
 
 // TestSpecJSON is a minimal valid CloudFormation spec for testing.
@@ -353,3 +357,63 @@ func TestLoadSpec_InvalidJSON(t *testing.T) {
 		t.Error("expected error for invalid JSON")
 	}
 }
+
+func loadSnapshot(t *testing.T) *spec.Spec {
+	t.Helper()
+	s, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("failed to load embedded spec: %v", err)
+	}
+	return s
+}
+
+func TestSnapshot_NestedPropertyTypes(t *testing.T) {
+	s := loadSnapshot(t)
+
+	bucket := s.GetResourceType("AWS::S3::Bucket")
+	if bucket == nil {
+		t.Fatal("expected AWS::S3::Bucket in snapshot")
+	}
+	enc := bucket.GetProperty("BucketEncryption")
+	if enc == nil || !enc.IsComplex() {
+		t.Fatalf("expected BucketEncryption to be a complex property, got %+v", enc)
+	}
+
+	pt := s.GetPropertyType(spec.GetPropertyTypeForResource("AWS::S3::Bucket", enc.Type))
+	if pt == nil {
+		t.Fatalf("expected property type for %s", enc.Type)
+	}
+	rules := pt.GetProperty("ServerSideEncryptionConfiguration")
+	if rules == nil || !rules.IsList() || rules.ItemType != "ServerSideEncryptionRule" {
+		t.Errorf("expected list of ServerSideEncryptionRule, got %+v", rules)
+	}
+
+	// Tags reference the shared Tag property type, which has no resource prefix.
+	tags := bucket.GetProperty("Tags")
+	if tags == nil || tags.ItemType != "Tag" {
+		t.Fatalf("expected Tags to be a list of Tag, got %+v", tags)
+	}
+	if !strings.Contains(tags.Documentation, "aws-resource-s3-bucket.html") {
+		t.Errorf("expected Tags documentation on the bucket page, got %s", tags.Documentation)
+	}
+	if s.HasPropertyType(spec.GetPropertyTypeForResource("AWS::S3::Bucket", "Tag")) {
+		t.Error("expected no resource-specific Tag property type")
+	}
+	if !s.HasPropertyType("Tag") {
+		t.Error("expected shared Tag property type")
+	}
+}
+
+func TestSnapshot_RequiredProperties(t *testing.T) {
+	s := loadSnapshot(t)
+
+	fn := s.GetResourceType("AWS::Lambda::Function")
+	if fn == nil {
+		t.Fatal("expected AWS::Lambda::Function in snapshot")
+	}
+	required := fn.GetRequiredProperties()
+	sort.Strings(required)
+	if want := []string{"Code", "Role"}; !reflect.DeepEqual(required, want) {
+		t.Errorf("expected required properties %v, got %v", want, required)
+	}
+}
@@ -5,6 +5,10 @@ type Spec struct {
 	ResourceSpecificationVersion string                  `json:"ResourceSpecificationVersion"`
 	ResourceTypes                map[string]ResourceType `json:"ResourceTypes"`
 	PropertyTypes                map[string]PropertyType `json:"PropertyTypes"`
+
+	// Provenance records the origin of types merged from registry schemas.
+	// Types without an entry come from the AWS resource specification.
+	Provenance map[string]Provenance `json:"Provenance,omitempty"`
 }
 
 // ResourceType is a CloudFormation resource type definition.
@@ -13,6 +17,10 @@ type ResourceType struct {
 	Attributes           map[string]Attribute `json:"Attributes"`
 	Properties           map[string]Property  `json:"Properties"`
 	AdditionalProperties bool                 `json:"AdditionalProperties"`
+
+	// Handlers lists the IAM permissions of each resource handler. It is only
+	// available for types loaded from registry schemas (see AttachHandlers).
+	Handlers map[Operation]Handler `json:"Handlers,omitempty"`
 }
 
 // PropertyType is a property type definition (nested structures).
@@ -0,0 +1,11 @@
+// Package specembed provides an embedded, compressed snapshot of the
+// CloudFormation Resource Specification for builds without network access:
+//
+//	cfSpec, err := specembed.Load()
+//	bucket := cfSpec.GetResourceType("AWS::S3::Bucket")
+//
+// Version reports the ResourceSpecificationVersion of the snapshot so
+// consumers can tell how stale it is. Regenerate the snapshot with:
+//
+//	go run ./cmd/specsnapshot
+package specembed
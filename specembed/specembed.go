@@ -0,0 +1,65 @@
+package specembed
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+//go:embed spec.json.gz
+var snapshot []byte
+
+// Load decodes the embedded spec snapshot.
+// Each call returns a fresh copy that the caller may modify.
+func Load() (*spec.Spec, error) {
+	data, err := Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	var s spec.Spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing embedded spec: %w", err)
+	}
+	return &s, nil
+}
+
+var shared struct {
+	once sync.Once
+	spec *spec.Spec
+	err  error
+}
+
+// Provider returns the embedded snapshot as a spec.SchemaProvider. The
+// snapshot is decoded once and shared between callers, so it must not be
+// modified; use Load for a private copy.
+func Provider() (spec.SchemaProvider, error) {
+	shared.once.Do(func() {
+		shared.spec, shared.err = Load()
+	})
+	if shared.err != nil {
+		return nil, shared.err
+	}
+	return shared.spec, nil
+}
+
+// Raw returns the uncompressed JSON of the embedded spec snapshot.
+func Raw() ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(snapshot))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing embedded spec: %w", err)
+	}
+	defer gz.Close()
+
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded spec: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,86 @@
+package specembed_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/specembed"
+)
+
+func TestLoad(t *testing.T) {
+	s, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if s.ResourceSpecificationVersion != specembed.Version {
+		t.Errorf("expected version %s, got %s", specembed.Version, s.ResourceSpecificationVersion)
+	}
+	if !s.HasResourceType("AWS::S3::Bucket") {
+		t.Error("expected snapshot to contain AWS::S3::Bucket")
+	}
+	if !s.HasPropertyType("Tag") {
+		t.Error("expected snapshot to contain the shared Tag property type")
+	}
+}
+
+// The published spec has well over a thousand resource types; a snapshot
+// far below that is a test fixture, not the spec.
+const (
+	minResourceTypes = 1000
+	minPropertyTypes = 5000
+)
+
+func TestLoad_FullSpec(t *testing.T) {
+	s, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if strings.Contains(specembed.Version, "seed") {
+		t.Errorf("snapshot version %s is a seed; regenerate with go run ./cmd/specsnapshot", specembed.Version)
+	}
+	if n := len(s.ResourceTypes); n < minResourceTypes {
+		t.Errorf("snapshot has %d resource types, want at least %d; regenerate with go run ./cmd/specsnapshot", n, minResourceTypes)
+	}
+	if n := len(s.PropertyTypes); n < minPropertyTypes {
+		t.Errorf("snapshot has %d property types, want at least %d; regenerate with go run ./cmd/specsnapshot", n, minPropertyTypes)
+	}
+}
+
+func TestLoad_FreshCopy(t *testing.T) {
+	first, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	delete(first.ResourceTypes, "AWS::S3::Bucket")
+
+	second, err := specembed.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !second.HasResourceType("AWS::S3::Bucket") {
+		t.Error("expected modifications to one loaded spec not to affect another")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	p, err := specembed.Provider()
+	if err != nil {
+		t.Fatalf("Provider failed: %v", err)
+	}
+	if p.Version() != specembed.Version {
+		t.Errorf("expected version %s, got %s", specembed.Version, p.Version())
+	}
+	if p.GetResourceType("AWS::S3::Bucket") == nil {
+		t.Error("expected provider to serve AWS::S3::Bucket")
+	}
+
+	again, err := specembed.Provider()
+	if err != nil {
+		t.Fatalf("Provider failed: %v", err)
+	}
+	if again != p {
+		t.Error("expected the provider to be shared")
+	}
+}
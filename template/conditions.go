@@ -0,0 +1,20 @@
+package template
+
+import "github.com/lex00/cloudformation-schema-go/intrinsics"
+
+// CheckConditions validates the template's conditions with
+// intrinsics.Conditions.Check, using the AllowedValues of its parameters
+// to find conditions that are always true or always false.
+func CheckConditions(t *Template) []intrinsics.ConditionIssue {
+	conditions := make(intrinsics.Conditions, len(t.Conditions))
+	for name, c := range t.Conditions {
+		conditions[name] = LongFormValue(c.Expression)
+	}
+	allowed := make(map[string][]any)
+	for name, p := range t.Parameters {
+		if len(p.AllowedValues) > 0 {
+			allowed[name] = p.AllowedValues
+		}
+	}
+	return conditions.Check(allowed)
+}
@@ -0,0 +1,47 @@
+package template_test
+
+import (
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+	"github.com/lex00/cloudformation-schema-go/template"
+)
+
+func TestCheckConditions(t *testing.T) {
+	tmpl, err := template.ParseTemplateContent([]byte(`
+Parameters:
+  Env:
+    Type: String
+    AllowedValues: [dev, prod]
+Conditions:
+  IsProd: !Equals [!Ref Env, prod]
+  IsDev: !Equals [!Ref Env, dev]
+  IsStaging: !Equals [!Ref Env, staging]
+  Lonely: !And [!Condition IsProd]
+  Loop: !Not [!Condition Loop]
+Resources:
+  Bucket:
+    Type: AWS::S3::Bucket
+`), "conditions.yaml")
+	if err != nil {
+		t.Fatalf("ParseTemplateContent failed: %v", err)
+	}
+
+	issues := template.CheckConditions(tmpl)
+	want := []struct {
+		condition string
+		code      intrinsics.ConditionIssueCode
+	}{
+		{"IsStaging", intrinsics.ConditionAlwaysFalse},
+		{"Lonely", intrinsics.ConditionArity},
+		{"Loop", intrinsics.ConditionCycle},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %v, want %d issues", issues, len(want))
+	}
+	for i, w := range want {
+		if issues[i].Condition != w.condition || issues[i].Code != w.code {
+			t.Errorf("issue %d: got %s, want %s %s", i, issues[i], w.condition, w.code)
+		}
+	}
+}
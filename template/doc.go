@@ -10,4 +10,13 @@
 //
 // Both YAML and JSON formats are supported, including short-form
 // intrinsic syntax (!Ref, !Sub, etc.) and long-form ({"Ref": ...}).
+//
+// Parsed intrinsics convert losslessly to the types of the intrinsics
+// package with ToTyped and ToTypedValue, and back with FromTyped:
+//
+//	typed, err := template.ToTypedValue(prop.Value) // e.g. intrinsics.GetAtt
+//	value := template.FromTyped(typed)              // equal to prop.Value
+//
+// CheckConditions validates the Conditions section against the parameters'
+// AllowedValues; see intrinsics.Conditions.Check.
 package template
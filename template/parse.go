@@ -536,9 +536,9 @@ func resolveLongFormIntrinsics(value any) any {
 		return nil
 	}
 
-	// Already converted
-	if _, ok := value.(*Intrinsic); ok {
-		return value
+	// Already converted; short-form arguments may still hold long forms
+	if in, ok := value.(*Intrinsic); ok {
+		return &Intrinsic{Type: in.Type, Args: resolveLongFormIntrinsics(in.Args)}
 	}
 
 	switch v := value.(type) {
@@ -648,6 +648,11 @@ func resolveLongFormIntrinsics(value any) any {
 
 					case "Transform":
 						return &Intrinsic{Type: IntrinsicTransform, Args: resolvedVal}
+
+					case "ValueOf":
+						if arr, ok := resolvedVal.([]any); ok && len(arr) >= 2 {
+							return &Intrinsic{Type: IntrinsicValueOf, Args: arr}
+						}
 					}
 				}
 
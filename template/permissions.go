@@ -0,0 +1,122 @@
+package template
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+)
+
+// Permissions is the set of IAM actions needed to deploy a template.
+type Permissions struct {
+	// Actions are all required actions, sorted and without duplicates.
+	Actions []string
+	// ByResourceType maps each resource type in the template to its actions.
+	ByResourceType map[string][]string
+	// Missing lists the resource types without handler permissions in the
+	// spec, such as custom resources, sorted. Their actions are not included.
+	Missing []string
+}
+
+// RequiredPermissions computes the IAM actions a deployment role needs to
+// run the given handler operations for every resource in the template.
+// If no operations are given, spec.DeploymentOperations is used.
+//
+// Handler permissions come from registry schemas; see spec.AttachHandlers.
+func RequiredPermissions(t *Template, s spec.SchemaProvider, ops ...spec.Operation) *Permissions {
+	if len(ops) == 0 {
+		ops = spec.DeploymentOperations
+	}
+
+	p := &Permissions{ByResourceType: make(map[string][]string)}
+	all := make(map[string]bool)
+	missing := make(map[string]bool)
+	for _, r := range t.Resources {
+		if _, done := p.ByResourceType[r.ResourceType]; done || missing[r.ResourceType] {
+			continue
+		}
+		actions, err := spec.RequiredActions(s, r.ResourceType, ops...)
+		if err != nil {
+			missing[r.ResourceType] = true
+			continue
+		}
+		p.ByResourceType[r.ResourceType] = actions
+		for _, a := range actions {
+			all[a] = true
+		}
+	}
+
+	for a := range all {
+		p.Actions = append(p.Actions, a)
+	}
+	sort.Strings(p.Actions)
+	for rt := range missing {
+		p.Missing = append(p.Missing, rt)
+	}
+	sort.Strings(p.Missing)
+	return p
+}
+
+// PolicyDocument is an IAM policy document.
+type PolicyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []PolicyStatement `json:"Statement"`
+}
+
+// PolicyStatement is a statement of an IAM policy document.
+type PolicyStatement struct {
+	Sid      string   `json:"Sid,omitempty"`
+	Effect   string   `json:"Effect"`
+	Action   []string `json:"Action"`
+	Resource string   `json:"Resource"`
+}
+
+// PolicyDocument renders the actions as an IAM policy allowing them on all
+// resources, with one statement per service prefix in prefix order.
+func (p *Permissions) PolicyDocument() *PolicyDocument {
+	doc := &PolicyDocument{Version: "2012-10-17", Statement: []PolicyStatement{}}
+	byService := make(map[string][]string)
+	var services []string
+	for _, action := range p.Actions {
+		service, _, _ := strings.Cut(action, ":")
+		if _, ok := byService[service]; !ok {
+			services = append(services, service)
+		}
+		byService[service] = append(byService[service], action)
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		doc.Statement = append(doc.Statement, PolicyStatement{
+			Sid:      statementID(service),
+			Effect:   "Allow",
+			Action:   byService[service],
+			Resource: "*",
+		})
+	}
+	return doc
+}
+
+// JSON returns the policy document as indented JSON.
+func (p *Permissions) JSON() ([]byte, error) {
+	return json.MarshalIndent(p.PolicyDocument(), "", "  ")
+}
+
+// statementID returns an alphanumeric Sid for a service prefix, e.g. "Ec2".
+func statementID(service string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range service {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
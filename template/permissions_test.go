@@ -0,0 +1,83 @@
+package template_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/spec"
+	"github.com/lex00/cloudformation-schema-go/template"
+)
+
+func permissionsSpec(t *testing.T) *spec.Spec {
+	t.Helper()
+	s := &spec.Spec{ResourceTypes: map[string]spec.ResourceType{
+		"AWS::S3::Bucket": {Handlers: map[spec.Operation]spec.Handler{
+			spec.OperationCreate: {Permissions: []string{"s3:CreateBucket", "s3:PutBucketTagging"}},
+			spec.OperationRead:   {Permissions: []string{"s3:GetBucketTagging"}},
+			spec.OperationDelete: {Permissions: []string{"s3:DeleteBucket"}},
+			spec.OperationList:   {Permissions: []string{"s3:ListAllMyBuckets"}},
+		}},
+		"AWS::SQS::Queue": {Handlers: map[spec.Operation]spec.Handler{
+			spec.OperationCreate: {Permissions: []string{"sqs:CreateQueue", "sqs:TagQueue"}},
+			spec.OperationUpdate: {Permissions: []string{"sqs:SetQueueAttributes", "sqs:TagQueue"}},
+		}},
+	}}
+	return s
+}
+
+func TestRequiredPermissions(t *testing.T) {
+	tmpl := template.NewTemplate()
+	tmpl.Resources["A"] = &template.Resource{LogicalID: "A", ResourceType: "AWS::S3::Bucket"}
+	tmpl.Resources["B"] = &template.Resource{LogicalID: "B", ResourceType: "AWS::S3::Bucket"}
+	tmpl.Resources["Q"] = &template.Resource{LogicalID: "Q", ResourceType: "AWS::SQS::Queue"}
+	tmpl.Resources["C"] = &template.Resource{LogicalID: "C", ResourceType: "Custom::Thing"}
+
+	p := template.RequiredPermissions(tmpl, permissionsSpec(t))
+
+	want := []string{
+		"s3:CreateBucket", "s3:DeleteBucket", "s3:GetBucketTagging", "s3:PutBucketTagging",
+		"sqs:CreateQueue", "sqs:SetQueueAttributes", "sqs:TagQueue",
+	}
+	if !reflect.DeepEqual(p.Actions, want) {
+		t.Errorf("Actions = %v, want %v", p.Actions, want)
+	}
+	if !reflect.DeepEqual(p.Missing, []string{"Custom::Thing"}) {
+		t.Errorf("Missing = %v", p.Missing)
+	}
+	if len(p.ByResourceType) != 2 {
+		t.Errorf("expected actions for 2 resource types, got %v", p.ByResourceType)
+	}
+
+	createOnly := template.RequiredPermissions(tmpl, permissionsSpec(t), spec.OperationCreate)
+	if len(createOnly.Actions) != 4 {
+		t.Errorf("expected 4 create actions, got %v", createOnly.Actions)
+	}
+}
+
+func TestPermissions_PolicyDocument(t *testing.T) {
+	tmpl := template.NewTemplate()
+	tmpl.Resources["A"] = &template.Resource{LogicalID: "A", ResourceType: "AWS::S3::Bucket"}
+	tmpl.Resources["Q"] = &template.Resource{LogicalID: "Q", ResourceType: "AWS::SQS::Queue"}
+
+	data, err := template.RequiredPermissions(tmpl, permissionsSpec(t), spec.OperationCreate).JSON()
+	if err != nil {
+		t.Fatalf("JSON failed: %v", err)
+	}
+
+	var doc template.PolicyDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid policy JSON: %v", err)
+	}
+	if doc.Version != "2012-10-17" || len(doc.Statement) != 2 {
+		t.Fatalf("unexpected policy %s", data)
+	}
+	s3 := doc.Statement[0]
+	if s3.Sid != "S3" || s3.Effect != "Allow" || s3.Resource != "*" ||
+		!reflect.DeepEqual(s3.Action, []string{"s3:CreateBucket", "s3:PutBucketTagging"}) {
+		t.Errorf("unexpected statement %+v", s3)
+	}
+	if doc.Statement[1].Sid != "Sqs" {
+		t.Errorf("Sid = %q, want Sqs", doc.Statement[1].Sid)
+	}
+}
@@ -0,0 +1,205 @@
+package template
+
+import (
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+)
+
+// longFormNames maps intrinsic types to their long-form JSON keys.
+var longFormNames = map[IntrinsicType]string{
+	IntrinsicRef:         "Ref",
+	IntrinsicGetAtt:      "Fn::GetAtt",
+	IntrinsicSub:         "Fn::Sub",
+	IntrinsicJoin:        "Fn::Join",
+	IntrinsicSelect:      "Fn::Select",
+	IntrinsicGetAZs:      "Fn::GetAZs",
+	IntrinsicIf:          "Fn::If",
+	IntrinsicEquals:      "Fn::Equals",
+	IntrinsicAnd:         "Fn::And",
+	IntrinsicOr:          "Fn::Or",
+	IntrinsicNot:         "Fn::Not",
+	IntrinsicCondition:   "Condition",
+	IntrinsicFindInMap:   "Fn::FindInMap",
+	IntrinsicBase64:      "Fn::Base64",
+	IntrinsicCidr:        "Fn::Cidr",
+	IntrinsicImportValue: "Fn::ImportValue",
+	IntrinsicSplit:       "Fn::Split",
+	IntrinsicTransform:   "Fn::Transform",
+	IntrinsicValueOf:     "Fn::ValueOf",
+}
+
+// LongForm returns the intrinsic in JSON long form, such as
+// {"Fn::GetAtt": ["MyRole", "Arn"]}. Nested intrinsics are converted too.
+func (in *Intrinsic) LongForm() map[string]any {
+	args := LongFormValue(in.Args)
+	switch in.Type {
+	case IntrinsicNot:
+		args = []any{args}
+	case IntrinsicGetAtt:
+		if parts, ok := in.Args.([]string); ok {
+			list := make([]any, len(parts))
+			for i, p := range parts {
+				list[i] = p
+			}
+			args = list
+		}
+	}
+	return map[string]any{longFormNames[in.Type]: args}
+}
+
+// LongFormValue replaces every *Intrinsic in a tree of maps and slices
+// with its long form.
+func LongFormValue(v any) any {
+	switch v := v.(type) {
+	case *Intrinsic:
+		if v == nil {
+			return nil
+		}
+		return v.LongForm()
+	case map[string]any:
+		result := make(map[string]any, len(v))
+		for key, value := range v {
+			result[key] = LongFormValue(value)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = LongFormValue(item)
+		}
+		return result
+	}
+	return v
+}
+
+// ToTyped converts a parsed intrinsic, with the intrinsics nested in its
+// arguments, into the types of the intrinsics package. Forms those types
+// cannot represent stay long-form maps; see intrinsics.Decode.
+func ToTyped(in *Intrinsic) (any, error) {
+	return intrinsics.Decode(in.LongForm())
+}
+
+// ToTypedValue is like ToTyped for any parsed value, such as a property
+// value with intrinsics nested in maps and slices.
+func ToTypedValue(v any) (any, error) {
+	return intrinsics.Decode(LongFormValue(v))
+}
+
+// FromTyped converts values of the intrinsics package, at any depth of a
+// tree of maps and slices, into parsed intrinsics. It is the inverse of
+// ToTypedValue: FromTyped(ToTypedValue(v)) equals v for parsed values,
+// except that a Select index written as a string, as in !Select ["0", ...],
+// comes back as the integer it stands for. Long-form intrinsic maps are
+// converted like the parser does.
+func FromTyped(v any) any {
+	switch v := v.(type) {
+	case intrinsics.Ref:
+		return &Intrinsic{Type: IntrinsicRef, Args: v.LogicalName}
+	case intrinsics.GetAtt:
+		return &Intrinsic{Type: IntrinsicGetAtt, Args: []string{v.LogicalName, v.Attribute}}
+	case intrinsics.Sub:
+		return &Intrinsic{Type: IntrinsicSub, Args: v.String}
+	case intrinsics.SubWithMap:
+		return &Intrinsic{Type: IntrinsicSub, Args: []any{v.String, fromTypedMap(v.Variables)}}
+	case intrinsics.Join:
+		return &Intrinsic{Type: IntrinsicJoin, Args: []any{v.Delimiter, fromTypedSlice(v.Values)}}
+	case intrinsics.Select:
+		return &Intrinsic{Type: IntrinsicSelect, Args: []any{v.Index, FromTyped(v.List)}}
+	case intrinsics.GetAZs:
+		return &Intrinsic{Type: IntrinsicGetAZs, Args: v.Region}
+	case intrinsics.If:
+		return &Intrinsic{Type: IntrinsicIf, Args: []any{v.Condition, FromTyped(v.ValueIfTrue), FromTyped(v.ValueIfFalse)}}
+	case intrinsics.Equals:
+		return &Intrinsic{Type: IntrinsicEquals, Args: []any{FromTyped(v.Value1), FromTyped(v.Value2)}}
+	case intrinsics.And:
+		return &Intrinsic{Type: IntrinsicAnd, Args: fromTypedSlice(v.Conditions)}
+	case intrinsics.Or:
+		return &Intrinsic{Type: IntrinsicOr, Args: fromTypedSlice(v.Conditions)}
+	case intrinsics.Not:
+		return &Intrinsic{Type: IntrinsicNot, Args: FromTyped(v.Condition)}
+	case intrinsics.Condition:
+		return &Intrinsic{Type: IntrinsicCondition, Args: v.Name}
+	case intrinsics.FindInMap:
+		return &Intrinsic{Type: IntrinsicFindInMap, Args: []any{v.MapName, FromTyped(v.TopKey), FromTyped(v.SecondKey)}}
+	case intrinsics.Base64:
+		return &Intrinsic{Type: IntrinsicBase64, Args: FromTyped(v.Value)}
+	case intrinsics.Cidr:
+		return &Intrinsic{Type: IntrinsicCidr, Args: []any{FromTyped(v.IPBlock), FromTyped(v.Count), FromTyped(v.CidrBits)}}
+	case intrinsics.ImportValue:
+		return &Intrinsic{Type: IntrinsicImportValue, Args: FromTyped(v.ExportName)}
+	case intrinsics.Split:
+		return &Intrinsic{Type: IntrinsicSplit, Args: []any{v.Delimiter, FromTyped(v.Source)}}
+	case intrinsics.Transform:
+		args := map[string]any{"Name": v.Name}
+		if v.Parameters != nil {
+			args["Parameters"] = fromTypedMap(v.Parameters)
+		}
+		return &Intrinsic{Type: IntrinsicTransform, Args: args}
+	case intrinsics.ValueOf:
+		return &Intrinsic{Type: IntrinsicValueOf, Args: []any{v.ParameterLogicalID, v.Attribute}}
+	case intrinsics.GetAttDynamic:
+		return longForm("Fn::GetAtt", []any{v.LogicalName, v.Attribute})
+	case intrinsics.FindInMapWithDefault:
+		return longForm("Fn::FindInMap", []any{v.MapName, v.TopKey, v.SecondKey, map[string]any{"DefaultValue": v.DefaultValue}})
+	case intrinsics.Length:
+		return longForm("Fn::Length", v.Value)
+	case intrinsics.ToJsonString:
+		return longForm("Fn::ToJsonString", v.Value)
+	case intrinsics.ForEach:
+		return longForm(v.Key(), []any{v.Identifier, v.Collection, v.Output})
+	case intrinsics.Contains:
+		return longForm("Fn::Contains", []any{v.List, v.Value})
+	case intrinsics.EachMemberEquals:
+		return longForm("Fn::EachMemberEquals", []any{v.List, v.Value})
+	case intrinsics.EachMemberIn:
+		return longForm("Fn::EachMemberIn", []any{v.StringsToCheck, v.StringsToMatch})
+	case intrinsics.RefAll:
+		return longForm("Fn::RefAll", v.ParameterType)
+	case intrinsics.ValueOfAll:
+		return longForm("Fn::ValueOfAll", []any{v.ParameterType, v.Attribute})
+	case intrinsics.Tag:
+		return map[string]any{"Key": v.Key, "Value": FromTyped(v.Value)}
+	case intrinsics.String:
+		return string(v)
+	case intrinsics.Int:
+		return int(v)
+	case intrinsics.Bool:
+		return bool(v)
+	case interface{ Items() []any }:
+		return fromTypedSlice(v.Items())
+	case interface{ Wrapped() any }:
+		return FromTyped(v.Wrapped())
+	case map[string]any:
+		return resolveLongFormIntrinsics(fromTypedMap(v))
+	case []any:
+		return fromTypedSlice(v)
+	}
+	return v
+}
+
+func fromTypedMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]any, len(m))
+	for key, value := range m {
+		result[key] = FromTyped(value)
+	}
+	return result
+}
+
+func fromTypedSlice(s []any) []any {
+	if s == nil {
+		return nil
+	}
+	result := make([]any, len(s))
+	for i, item := range s {
+		result[i] = FromTyped(item)
+	}
+	return result
+}
+
+// longForm returns a long-form map for intrinsics the parser has no
+// IntrinsicType for, converting the arguments.
+func longForm(name string, args any) map[string]any {
+	return map[string]any{name: FromTyped(args)}
+}
@@ -0,0 +1,179 @@
+package template_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lex00/cloudformation-schema-go/intrinsics"
+	"github.com/lex00/cloudformation-schema-go/template"
+)
+
+const typedTemplate = `
+Conditions:
+  IsProd: !Equals [!Ref Env, prod]
+  NotProd: !Not [!Condition IsProd]
+  Either: !Or [!Condition IsProd, !And [!Condition NotProd, !Equals [a, b]]]
+Resources:
+  Test:
+    Type: AWS::S3::Bucket
+    Properties:
+      Ref: !Ref Param
+      GetAtt: !GetAtt Role.Arn
+      Sub: !Sub "${AWS::Region}-x"
+      SubMap: !Sub ["${B}-x", {B: !Ref Bucket}]
+      Join: !Join ["", ["arn:", !Ref "AWS::Partition", ":s3:::", !Ref Bucket]]
+      JoinList: !Join [",", !Ref Subnets]
+      Select: !Select [0, !GetAZs ""]
+      If: !If [IsProd, !GetAtt Db.Endpoint.Address, !Ref "AWS::NoValue"]
+      FindInMap: !FindInMap [Map, !Ref "AWS::Region", Ami]
+      Base64: !Base64 {"Fn::Sub": "echo ${X}"}
+      Cidr: !Cidr [!GetAtt Vpc.CidrBlock, 6, 8]
+      Import: !ImportValue shared-vpc
+      Split: !Split [",", !ImportValue subnets]
+      Transform: !Transform {Name: "AWS::Include", Parameters: {Location: "s3://b/k"}}
+      ValueOf: !ValueOf [Param, Value]
+      Nested:
+        Tags:
+          - Key: Name
+            Value: !Sub "${AWS::StackName}"
+        Count: 3
+`
+
+func TestToTypedValue(t *testing.T) {
+	tmpl, err := template.ParseTemplateContent([]byte(typedTemplate), "typed.yaml")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	props := tmpl.Resources["Test"].Properties
+
+	tests := []struct {
+		prop string
+		want any
+	}{
+		{"Ref", intrinsics.Ref{LogicalName: "Param"}},
+		{"GetAtt", intrinsics.GetAtt{LogicalName: "Role", Attribute: "Arn"}},
+		{"Sub", intrinsics.Sub{String: "${AWS::Region}-x"}},
+		{"SubMap", intrinsics.SubWithMap{String: "${B}-x", Variables: map[string]any{"B": intrinsics.Ref{LogicalName: "Bucket"}}}},
+		{"Join", intrinsics.Join{Values: []any{"arn:", intrinsics.AWS_PARTITION, ":s3:::", intrinsics.Ref{LogicalName: "Bucket"}}}},
+		{"JoinList", map[string]any{"Fn::Join": []any{",", intrinsics.Ref{LogicalName: "Subnets"}}}},
+		{"Select", intrinsics.Select{Index: 0, List: intrinsics.GetAZs{}}},
+		{"If", intrinsics.If{Condition: "IsProd", ValueIfTrue: intrinsics.GetAtt{LogicalName: "Db", Attribute: "Endpoint.Address"}, ValueIfFalse: intrinsics.AWS_NO_VALUE}},
+		{"FindInMap", intrinsics.FindInMap{MapName: "Map", TopKey: intrinsics.AWS_REGION, SecondKey: "Ami"}},
+		{"Base64", intrinsics.Base64{Value: intrinsics.Sub{String: "echo ${X}"}}},
+		{"Cidr", intrinsics.Cidr{IPBlock: intrinsics.GetAtt{LogicalName: "Vpc", Attribute: "CidrBlock"}, Count: 6, CidrBits: 8}},
+		{"Import", intrinsics.ImportValue{ExportName: "shared-vpc"}},
+		{"Split", intrinsics.Split{Delimiter: ",", Source: intrinsics.ImportValue{ExportName: "subnets"}}},
+		{"Transform", intrinsics.Transform{Name: "AWS::Include", Parameters: map[string]any{"Location": "s3://b/k"}}},
+		{"ValueOf", intrinsics.ValueOf{ParameterLogicalID: "Param", Attribute: "Value"}},
+		{"Nested", map[string]any{
+			"Tags":  []any{map[string]any{"Key": "Name", "Value": intrinsics.Sub{String: "${AWS::StackName}"}}},
+			"Count": 3,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prop, func(t *testing.T) {
+			got, err := template.ToTypedValue(props[tt.prop].Value)
+			if err != nil {
+				t.Fatalf("ToTypedValue failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromTyped_RoundTrip(t *testing.T) {
+	tmpl, err := template.ParseTemplateContent([]byte(typedTemplate), "typed.yaml")
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	values := make(map[string]any)
+	for name, prop := range tmpl.Resources["Test"].Properties {
+		values["Properties."+name] = prop.Value
+	}
+	for name, cond := range tmpl.Conditions {
+		values["Conditions."+name] = cond.Expression
+	}
+
+	for name, v := range values {
+		t.Run(name, func(t *testing.T) {
+			typed, err := template.ToTypedValue(v)
+			if err != nil {
+				t.Fatalf("ToTypedValue failed: %v", err)
+			}
+			if got := template.FromTyped(typed); !reflect.DeepEqual(got, v) {
+				t.Errorf("got %#v, want %#v", got, v)
+			}
+		})
+	}
+}
+
+func TestToTyped(t *testing.T) {
+	in := &template.Intrinsic{Type: template.IntrinsicNot, Args: &template.Intrinsic{Type: template.IntrinsicCondition, Args: "IsProd"}}
+	if got := in.LongForm(); !reflect.DeepEqual(got, map[string]any{"Fn::Not": []any{map[string]any{"Condition": "IsProd"}}}) {
+		t.Errorf("LongForm() = %#v", got)
+	}
+
+	got, err := template.ToTyped(in)
+	if err != nil {
+		t.Fatalf("ToTyped failed: %v", err)
+	}
+	if want := (intrinsics.Not{Condition: intrinsics.Condition{Name: "IsProd"}}); got != want {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	// A numeric-string Select index decodes, and converts back as an int.
+	sel := &template.Intrinsic{Type: template.IntrinsicSelect, Args: []any{"1", []any{"a", "b"}}}
+	got, err = template.ToTyped(sel)
+	if err != nil {
+		t.Fatalf("ToTyped failed: %v", err)
+	}
+	if want := (intrinsics.Select{Index: 1, List: []any{"a", "b"}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+	back := template.FromTyped(got)
+	if want := (&template.Intrinsic{Type: template.IntrinsicSelect, Args: []any{1, []any{"a", "b"}}}); !reflect.DeepEqual(back, want) {
+		t.Errorf("FromTyped = %#v, want %#v", back, want)
+	}
+
+	if _, err := template.ToTyped(&template.Intrinsic{Type: template.IntrinsicRef, Args: 1}); err == nil {
+		t.Error("expected error for Ref with non-string argument")
+	}
+}
+
+func TestFromTyped(t *testing.T) {
+	got := template.FromTyped(intrinsics.Tag{Key: "Env", Value: intrinsics.Ref{LogicalName: "Env"}})
+	want := map[string]any{"Key": "Env", "Value": &template.Intrinsic{Type: template.IntrinsicRef, Args: "Env"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	// Long-form maps are recognized like the parser does.
+	got = template.FromTyped(map[string]any{"Fn::GetAtt": []any{"Role", "Arn"}})
+	want2 := &template.Intrinsic{Type: template.IntrinsicGetAtt, Args: []string{"Role", "Arn"}}
+	if !reflect.DeepEqual(got, want2) {
+		t.Errorf("got %#v, want %#v", got, want2)
+	}
+}
+
+func TestFromTyped_TypedValues(t *testing.T) {
+	got := template.FromTyped(intrinsics.JoinOf("-", intrinsics.String("a"), intrinsics.Ref{LogicalName: "B"}))
+	want := &template.Intrinsic{Type: template.IntrinsicJoin, Args: []any{"-", []any{"a", &template.Intrinsic{Type: template.IntrinsicRef, Args: "B"}}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	got = template.FromTyped(intrinsics.ListOf[intrinsics.StringValue]{Value: intrinsics.Ref{LogicalName: "Subnets"}})
+	if want := (&template.Intrinsic{Type: template.IntrinsicRef, Args: "Subnets"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	got = template.FromTyped(intrinsics.JoinList(",", intrinsics.GetAttList{LogicalName: "Vpc", Attribute: "CidrBlockAssociations"}))
+	want = &template.Intrinsic{Type: template.IntrinsicJoin, Args: []any{",", &template.Intrinsic{Type: template.IntrinsicGetAtt, Args: []string{"Vpc", "CidrBlockAssociations"}}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}